@@ -0,0 +1,116 @@
+package turso
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditLogCode identifies the kind of action an audit log records.
+type AuditLogCode string
+
+const (
+	AuditLogDatabaseCreate AuditLogCode = "db-create"
+	AuditLogDatabaseDelete AuditLogCode = "db-delete"
+	AuditLogGroupCreate    AuditLogCode = "group-create"
+	AuditLogGroupDelete    AuditLogCode = "group-delete"
+	AuditLogMemberAdd      AuditLogCode = "member-add"
+	AuditLogMemberRemove   AuditLogCode = "member-remove"
+	AuditLogTokenMint      AuditLogCode = "token-mint"
+)
+
+type DatabaseCreateData struct {
+	Name  string `json:"name"`
+	Group string `json:"group"`
+}
+
+type DatabaseDeleteData struct {
+	Name string `json:"name"`
+}
+
+type GroupCreateData struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+}
+
+type GroupDeleteData struct {
+	Name string `json:"name"`
+}
+
+type MemberAddData struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+type MemberRemoveData struct {
+	Username string `json:"username"`
+}
+
+// TokenMintData sets Database for database tokens and Group for group
+// tokens.
+type TokenMintData struct {
+	Database      string `json:"database"`
+	Group         string `json:"group"`
+	Authorization string `json:"authorization"`
+	Expiration    string `json:"expiration"`
+}
+
+// auditLogPayloads returns an empty payload for each known code.
+var auditLogPayloads = map[AuditLogCode]func() interface{}{
+	AuditLogDatabaseCreate: func() interface{} { return &DatabaseCreateData{} },
+	AuditLogDatabaseDelete: func() interface{} { return &DatabaseDeleteData{} },
+	AuditLogGroupCreate:    func() interface{} { return &GroupCreateData{} },
+	AuditLogGroupDelete:    func() interface{} { return &GroupDeleteData{} },
+	AuditLogMemberAdd:      func() interface{} { return &MemberAddData{} },
+	AuditLogMemberRemove:   func() interface{} { return &MemberRemoveData{} },
+	AuditLogTokenMint:      func() interface{} { return &TokenMintData{} },
+}
+
+// AuditLogEvent is an AuditLog with its timestamp parsed and its data
+// decoded.
+type AuditLogEvent struct {
+	Author    string
+	Code      AuditLogCode
+	CreatedAt time.Time
+	Message   string
+	Origin    string
+	// Payload points to the data struct of a known Code, such as
+	// *DatabaseCreateData, and is the json.RawMessage sent by the API for
+	// other codes.
+	Payload interface{}
+}
+
+// Event parses the log into an AuditLogEvent.
+func (l AuditLog) Event() (AuditLogEvent, error) {
+	event := AuditLogEvent{
+		Author:  l.Author,
+		Code:    AuditLogCode(l.Code),
+		Message: l.Message,
+		Origin:  l.Origin,
+	}
+	createdAt, err := time.Parse(time.RFC3339, l.CreatedAt)
+	if err != nil {
+		return event, fmt.Errorf("parsing audit log created_at: %w", err)
+	}
+	event.CreatedAt = createdAt
+	data := l.Data
+	if len(data) == 0 {
+		data = json.RawMessage("null")
+	}
+	newPayload, ok := auditLogPayloads[event.Code]
+	if !ok {
+		event.Payload = data
+		return event, nil
+	}
+	payload := newPayload()
+	if err := json.Unmarshal(data, payload); err != nil {
+		return event, fmt.Errorf("decoding %s audit log data: %w", l.Code, err)
+	}
+	event.Payload = payload
+	return event, nil
+}
+
+// Event returns the log Next advanced to as an AuditLogEvent.
+func (it *AuditLogIterator) Event() (AuditLogEvent, error) {
+	return it.current.Event()
+}
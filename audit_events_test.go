@@ -0,0 +1,85 @@
+package turso
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mr-destructive/turso-go/internal/fakeapi"
+)
+
+func TestAuditLogEvent(t *testing.T) {
+	log := AuditLog{
+		Author:    "alice",
+		Code:      "db-create",
+		CreatedAt: "2024-03-01T12:30:00Z",
+		Data:      json.RawMessage(`{"name": "my-db", "group": "default"}`),
+		Message:   "created database my-db",
+		Origin:    "cli",
+	}
+	event, err := log.Event()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Code != AuditLogDatabaseCreate || !event.CreatedAt.Equal(time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected event %+v", event)
+	}
+	payload, ok := event.Payload.(*DatabaseCreateData)
+	if !ok || payload.Name != "my-db" || payload.Group != "default" {
+		t.Errorf("unexpected payload %#v", event.Payload)
+	}
+}
+
+func TestAuditLogEventUnknownCode(t *testing.T) {
+	data := `{"zone":"b","id":9007199254740993,"answer":42}`
+	log := AuditLog{Code: "future-thing", CreatedAt: "2024-03-01T12:30:00Z", Data: json.RawMessage(data)}
+	event, err := log.Event()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, ok := event.Payload.(json.RawMessage)
+	if !ok || string(raw) != data {
+		t.Errorf("the payload should be the data sent by the API, got %#v", event.Payload)
+	}
+	fields, err := log.DataMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["id"] != json.Number("9007199254740993") || fields["zone"] != "b" {
+		t.Errorf("unexpected data %#v", fields)
+	}
+}
+
+func TestAuditLogEventErrors(t *testing.T) {
+	if _, err := (AuditLog{Code: "db-create", CreatedAt: "yesterday"}).Event(); err == nil {
+		t.Error("expected an error for an invalid created_at")
+	}
+	log := AuditLog{Code: "db-create", CreatedAt: "2024-03-01T12:30:00Z", Data: json.RawMessage(`{"name": 1}`)}
+	if _, err := log.Event(); err == nil {
+		t.Error("expected an error for a mistyped payload")
+	}
+}
+
+func TestAuditLogIteratorEvents(t *testing.T) {
+	client, _ := newTestClient(t)
+	org := fakeapi.DefaultOrganization
+	if _, err := client.Organizations.CreateDatabase(org, CreateDatabaseRequest{Name: "events", Group: fakeapi.DefaultGroup}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Organizations.MintToken(org, "events", MintTokenOptions{Expiration: 24 * time.Hour, Authorization: ReadOnly}); err != nil {
+		t.Fatal(err)
+	}
+	it := IterateAuditLogs(context.Background(), client.AuditLogs, org, AuditLogFilter{Codes: []string{string(AuditLogTokenMint)}})
+	if !it.Next() {
+		t.Fatalf("expected a token-mint log: %v", it.Err())
+	}
+	event, err := it.Event()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, ok := event.Payload.(*TokenMintData)
+	if !ok || payload.Database != "events" || payload.Authorization != "read-only" || payload.Expiration != "1d" {
+		t.Errorf("unexpected payload %#v", event.Payload)
+	}
+}
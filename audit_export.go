@@ -0,0 +1,433 @@
+package turso
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditLogFormat is the format written by an AuditLogEncoder.
+type AuditLogFormat string
+
+const (
+	// AuditLogJSONL writes one JSON object per line.
+	AuditLogJSONL AuditLogFormat = "jsonl"
+	// AuditLogCSV writes a header and one row per log, with the columns of
+	// AuditLogEncoderOptions.Columns.
+	AuditLogCSV AuditLogFormat = "csv"
+	// AuditLogSyslog writes RFC 5424 syslog lines.
+	AuditLogSyslog AuditLogFormat = "syslog"
+	// AuditLogCEF writes ArcSight Common Event Format lines.
+	AuditLogCEF AuditLogFormat = "cef"
+)
+
+// AuditLogEncoder writes audit logs in one format. Flush must be called
+// once every log has been encoded.
+type AuditLogEncoder interface {
+	Encode(log AuditLog) error
+	Flush() error
+}
+
+// AuditLogEncoderOptions sets the fields of syslog lines that do not come
+// from the logs, and the CSV columns.
+type AuditLogEncoderOptions struct {
+	// Hostname is the syslog HOSTNAME; "-" when empty.
+	Hostname string
+	// AppName is the syslog APP-NAME; "turso" when empty.
+	AppName string
+	// Columns are the CSV columns, in order; DefaultAuditLogColumns when
+	// empty. A column is created_at, code, author, origin, message,
+	// data.<key> for one key of Data, nested keys joined with dots, or data
+	// for the whole of Data as JSON.
+	Columns []string
+	// OmitHeader skips the CSV header, for appending to output that already
+	// starts with one.
+	OmitHeader bool
+}
+
+// DefaultAuditLogColumns are the CSV columns written when none are set:
+// the fields of the logs, then a data.<key> column for each key of the
+// payloads of the known codes, such as data.name. The keys of other codes
+// need a column of their own, or the data column.
+var DefaultAuditLogColumns = append([]string{"created_at", "code", "author", "origin", "message"}, auditLogDataColumns()...)
+
+// auditLogDataColumns returns the sorted data.<key> columns of the keys of
+// the payloads of the known codes.
+func auditLogDataColumns() []string {
+	seen := map[string]bool{}
+	var columns []string
+	for _, newPayload := range auditLogPayloads {
+		payload := reflect.TypeOf(newPayload()).Elem()
+		for i := 0; i < payload.NumField(); i++ {
+			key, _, _ := strings.Cut(payload.Field(i).Tag.Get("json"), ",")
+			if key != "" && key != "-" && !seen[key] {
+				seen[key] = true
+				columns = append(columns, "data."+key)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// NewAuditLogEncoder returns an encoder writing format to w.
+func NewAuditLogEncoder(w io.Writer, format AuditLogFormat, opts AuditLogEncoderOptions) (AuditLogEncoder, error) {
+	if opts.Hostname == "" {
+		opts.Hostname = "-"
+	}
+	if opts.AppName == "" {
+		opts.AppName = "turso"
+	}
+	switch format {
+	case AuditLogJSONL:
+		return &jsonlEncoder{w: bufio.NewWriter(w)}, nil
+	case AuditLogCSV:
+		columns := opts.Columns
+		if len(columns) == 0 {
+			columns = DefaultAuditLogColumns
+		}
+		for _, column := range columns {
+			if !validCSVColumn(column) {
+				return nil, fmt.Errorf("unknown audit log column %q", column)
+			}
+		}
+		return &csvEncoder{w: csv.NewWriter(w), columns: columns, header: !opts.OmitHeader}, nil
+	case AuditLogSyslog:
+		return &lineEncoder{w: bufio.NewWriter(w), format: func(log AuditLog) (string, error) {
+			return syslogLine(log, opts)
+		}}, nil
+	case AuditLogCEF:
+		return &lineEncoder{w: bufio.NewWriter(w), format: cefLine}, nil
+	}
+	return nil, fmt.Errorf("unknown audit log format %q", format)
+}
+
+type jsonlEncoder struct {
+	w *bufio.Writer
+}
+
+func (e *jsonlEncoder) Encode(log AuditLog) error {
+	data, err := json.Marshal(log)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+	return e.w.WriteByte('\n')
+}
+
+func (e *jsonlEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+// csvEncoder writes the header before the first row, unless it is
+// omitted, and streams the rows.
+type csvEncoder struct {
+	w       *csv.Writer
+	columns []string
+	header  bool
+}
+
+func validCSVColumn(column string) bool {
+	switch column {
+	case "created_at", "code", "author", "origin", "message", "data":
+		return true
+	}
+	return strings.HasPrefix(column, "data.") && len(column) > len("data.")
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if !e.header {
+		return nil
+	}
+	e.header = false
+	return e.w.Write(e.columns)
+}
+
+func (e *csvEncoder) Encode(log AuditLog) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	var flat map[string]string
+	record := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		switch column {
+		case "created_at":
+			record[i] = log.CreatedAt
+		case "code":
+			record[i] = log.Code
+		case "author":
+			record[i] = log.Author
+		case "origin":
+			record[i] = log.Origin
+		case "message":
+			record[i] = log.Message
+		case "data":
+			data, err := compactData(log)
+			if err != nil {
+				return err
+			}
+			record[i] = data
+		default:
+			if flat == nil {
+				data, err := log.DataMap()
+				if err != nil {
+					return err
+				}
+				flat = flattenData(data)
+			}
+			record[i] = flat[strings.TrimPrefix(column, "data.")]
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvEncoder) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type lineEncoder struct {
+	w      *bufio.Writer
+	format func(log AuditLog) (string, error)
+}
+
+func (e *lineEncoder) Encode(log AuditLog) error {
+	line, err := e.format(log)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.WriteString(line); err != nil {
+		return err
+	}
+	return e.w.WriteByte('\n')
+}
+
+func (e *lineEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+// flattenData turns nested data into dotted keys, encoding other non-string
+// values as JSON.
+func flattenData(data map[string]interface{}) map[string]string {
+	flat := map[string]string{}
+	var walk func(prefix string, value interface{})
+	walk = func(prefix string, value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, nested := range v {
+				walk(prefix+"."+key, nested)
+			}
+		case string:
+			flat[prefix[1:]] = v
+		case nil:
+			flat[prefix[1:]] = ""
+		default:
+			encoded, _ := json.Marshal(v)
+			flat[prefix[1:]] = string(encoded)
+		}
+	}
+	for key, value := range data {
+		walk("."+key, value)
+	}
+	return flat
+}
+
+// compactData returns the data of log as compact JSON, or "" when the log
+// has none.
+func compactData(log AuditLog) (string, error) {
+	if len(log.Data) == 0 {
+		return "", nil
+	}
+	var data bytes.Buffer
+	if err := json.Compact(&data, log.Data); err != nil {
+		return "", fmt.Errorf("decoding %s audit log data: %w", log.Code, err)
+	}
+	return data.String(), nil
+}
+
+func sortedFlatData(log AuditLog) ([]string, map[string]string, error) {
+	data, err := log.DataMap()
+	if err != nil {
+		return nil, nil, err
+	}
+	flat := flattenData(data)
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, flat, nil
+}
+
+// syslogSDID is the structured data ID of syslog lines, under the
+// enterprise number reserved for documentation.
+const syslogSDID = "turso@32473"
+
+// auditLogSeverity is the syslog severity of a log: notice for removals,
+// informational otherwise.
+func auditLogSeverity(code string) int {
+	if strings.HasSuffix(code, "-delete") || strings.HasSuffix(code, "-remove") {
+		return 5
+	}
+	return 6
+}
+
+func syslogLine(log AuditLog, opts AuditLogEncoderOptions) (string, error) {
+	createdAt, err := time.Parse(time.RFC3339, log.CreatedAt)
+	if err != nil {
+		return "", fmt.Errorf("parsing audit log created_at: %w", err)
+	}
+	// Facility 13 is "log audit".
+	priority := 13*8 + auditLogSeverity(log.Code)
+	var sd strings.Builder
+	sd.WriteString("[" + syslogSDID)
+	writeParam := func(name, value string) {
+		fmt.Fprintf(&sd, ` %s="%s"`, name, syslogEscaper.Replace(value))
+	}
+	writeParam("author", log.Author)
+	writeParam("origin", log.Origin)
+	keys, flat, err := sortedFlatData(log)
+	if err != nil {
+		return "", err
+	}
+	for _, key := range keys {
+		writeParam("data."+syslogParamName(key), flat[key])
+	}
+	sd.WriteString("]")
+	return fmt.Sprintf("<%d>1 %s %s %s - %s %s %s",
+		priority,
+		createdAt.UTC().Format(time.RFC3339),
+		opts.Hostname,
+		opts.AppName,
+		syslogField(log.Code),
+		sd.String(),
+		strings.ReplaceAll(log.Message, "\n", " "),
+	), nil
+}
+
+var syslogEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// syslogParamName drops the characters RFC 5424 forbids in a PARAM-NAME.
+func syslogParamName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+func syslogField(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return syslogParamName(value)
+}
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`)
+)
+
+func cefLine(log AuditLog) (string, error) {
+	createdAt, err := time.Parse(time.RFC3339, log.CreatedAt)
+	if err != nil {
+		return "", fmt.Errorf("parsing audit log created_at: %w", err)
+	}
+	severity := 3
+	if auditLogSeverity(log.Code) == 5 {
+		severity = 6
+	}
+	data, err := compactData(log)
+	if err != nil {
+		return "", err
+	}
+	if data == "" {
+		data = "null"
+	}
+	extension := []string{
+		"rt=" + strconv.FormatInt(createdAt.UnixMilli(), 10),
+		"suser=" + cefExtensionEscaper.Replace(log.Author),
+		"msg=" + cefExtensionEscaper.Replace(log.Message),
+		"cs1Label=origin",
+		"cs1=" + cefExtensionEscaper.Replace(log.Origin),
+		"cs2Label=data",
+		"cs2=" + cefExtensionEscaper.Replace(data),
+	}
+	return fmt.Sprintf("CEF:0|Turso|Platform API|1|%s|%s|%d|%s",
+		cefHeaderEscaper.Replace(log.Code),
+		cefHeaderEscaper.Replace(log.Message),
+		severity,
+		strings.Join(extension, " "),
+	), nil
+}
+
+// AuditLogExportOptions configures ExportAuditLogs.
+type AuditLogExportOptions struct {
+	Format AuditLogFormat
+	// Filter selects the exported logs.
+	Filter AuditLogFilter
+	// Cursor makes the export incremental: only the logs newer than the
+	// stored cursor are written, and the cursor is saved once they are.
+	Cursor AuditLogCursorStore
+	// Encoder sets the syslog hostname and app name.
+	Encoder AuditLogEncoderOptions
+}
+
+// ExportAuditLogs writes the logs of service matching the filter to w,
+// oldest first. It writes each page as it is read, from the oldest page
+// down, so that only one page is held in memory. It returns the number of
+// logs written; when writing fails, the cursor is saved after the logs
+// written before the failure, so that the next export resumes after them.
+// The cursor also moves past the logs the filter skips.
+func ExportAuditLogs(ctx context.Context, service AuditLogsService, orgSlug string, w io.Writer, opts AuditLogExportOptions) (int, error) {
+	encoder, err := NewAuditLogEncoder(w, opts.Format, opts.Encoder)
+	if err != nil {
+		return 0, err
+	}
+	var cursor AuditLogCursor
+	if opts.Cursor != nil {
+		if cursor, err = opts.Cursor.Load(); err != nil {
+			return 0, err
+		}
+	}
+	n, advanced := 0, false
+	cursor, err = walkAuditLogs(ctx, service, orgSlug, opts.Filter.PageSize, opts.Filter.Since, cursor, func(log AuditLog, createdAt time.Time, next AuditLogCursor) error {
+		if opts.Filter.match(log, createdAt) {
+			// Flushing every log keeps n and the cursor in step with w.
+			if err := encoder.Encode(log); err != nil {
+				return err
+			}
+			if err := encoder.Flush(); err != nil {
+				return err
+			}
+			n++
+		}
+		advanced = true
+		return nil
+	})
+	if err == nil {
+		// Writes the CSV header of an empty export.
+		err = encoder.Flush()
+	}
+	if opts.Cursor != nil && advanced {
+		if saveErr := opts.Cursor.Save(cursor); err == nil {
+			err = saveErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,232 @@
+package turso
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mr-destructive/turso-go/internal/fakeapi"
+)
+
+var exportLogs = []AuditLog{
+	{
+		Author:    "alice",
+		Code:      "db-create",
+		CreatedAt: "2024-03-01T12:30:00Z",
+		Data:      json.RawMessage(`{"group": "default", "name": "my-db", "seed": {"type": "database"}}`),
+		Message:   "created database my-db",
+		Origin:    "cli",
+	},
+	{
+		Author:    "bob",
+		Code:      "member-remove",
+		CreatedAt: "2024-03-01T13:00:00Z",
+		Data:      json.RawMessage(`{"username": "eve \"the=admin\""}`),
+		Message:   "removed eve | admin",
+		Origin:    "api",
+	},
+}
+
+func encodeAuditLogs(t *testing.T, format AuditLogFormat) string {
+	t.Helper()
+	var buf bytes.Buffer
+	encoder, err := NewAuditLogEncoder(&buf, format, AuditLogEncoderOptions{Hostname: "siem"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, log := range exportLogs {
+		if err := encoder.Encode(log); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestAuditLogEncoderJSONL(t *testing.T) {
+	lines := strings.Split(strings.TrimSuffix(encodeAuditLogs(t, AuditLogJSONL), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", lines)
+	}
+	var log AuditLog
+	if err := json.Unmarshal([]byte(lines[1]), &log); err != nil || log.Code != "member-remove" {
+		t.Errorf("unexpected line %s: %v", lines[1], err)
+	}
+}
+
+func TestAuditLogEncoderCSV(t *testing.T) {
+	records, err := csv.NewReader(strings.NewReader(encodeAuditLogs(t, AuditLogCSV))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(records[0], ","), strings.Join(DefaultAuditLogColumns, ","); got != want {
+		t.Errorf("got header %s, want %s", got, want)
+	}
+	if got := strings.Join(records[1][:5], ","); got != "2024-03-01T12:30:00Z,db-create,alice,cli,created database my-db" {
+		t.Errorf("unexpected row %s", got)
+	}
+	row := map[string]string{}
+	for i, column := range records[0] {
+		row[column] = records[2][i]
+	}
+	if row["data.username"] != `eve "the=admin"` || row["data.name"] != "" {
+		t.Errorf("unexpected data columns %q", row)
+	}
+	for _, column := range []string{"data.name", "data.group", "data.location", "data.username", "data.role", "data.database", "data.authorization", "data.expiration"} {
+		if !containsString(DefaultAuditLogColumns, column) {
+			t.Errorf("default columns %v miss %s", DefaultAuditLogColumns, column)
+		}
+	}
+}
+
+func TestAuditLogEncoderCSVColumns(t *testing.T) {
+	var buf bytes.Buffer
+	opts := AuditLogEncoderOptions{Columns: []string{"code", "data.seed.type", "data.username", "data"}}
+	for i, log := range exportLogs {
+		// The second run appends to the output of the first.
+		opts.OmitHeader = i > 0
+		encoder, err := NewAuditLogEncoder(&buf, AuditLogCSV, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := encoder.Encode(log); err != nil {
+			t.Fatal(err)
+		}
+		if err := encoder.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := `code,data.seed.type,data.username,data
+db-create,database,,"{""group"":""default"",""name"":""my-db"",""seed"":{""type"":""database""}}"
+member-remove,,"eve ""the=admin""","{""username"":""eve \""the=admin\""""}"
+`
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	if _, err := NewAuditLogEncoder(&buf, AuditLogCSV, AuditLogEncoderOptions{Columns: []string{"name"}}); err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+}
+
+func TestAuditLogEncoderSyslog(t *testing.T) {
+	lines := strings.Split(encodeAuditLogs(t, AuditLogSyslog), "\n")
+	want := `<110>1 2024-03-01T12:30:00Z siem turso - db-create [turso@32473 author="alice" origin="cli" data.group="default" data.name="my-db" data.seed.type="database"] created database my-db`
+	if lines[0] != want {
+		t.Errorf("got  %s\nwant %s", lines[0], want)
+	}
+	want = `<109>1 2024-03-01T13:00:00Z siem turso - member-remove [turso@32473 author="bob" origin="api" data.username="eve \"the=admin\""] removed eve | admin`
+	if lines[1] != want {
+		t.Errorf("got  %s\nwant %s", lines[1], want)
+	}
+}
+
+func TestAuditLogEncoderCEF(t *testing.T) {
+	lines := strings.Split(encodeAuditLogs(t, AuditLogCEF), "\n")
+	want := `CEF:0|Turso|Platform API|1|member-remove|removed eve \| admin|6|rt=1709298000000 suser=bob msg=removed eve | admin cs1Label=origin cs1=api cs2Label=data cs2={"username":"eve \\"the\=admin\\""}`
+	if lines[1] != want {
+		t.Errorf("got  %s\nwant %s", lines[1], want)
+	}
+}
+
+func TestAuditLogEncoderUnknownFormat(t *testing.T) {
+	if _, err := NewAuditLogEncoder(&bytes.Buffer{}, "xml", AuditLogEncoderOptions{}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestAuditLogsExportIncremental(t *testing.T) {
+	client, server := newTestClient(t)
+	stopClock(server, testStart)
+	org := fakeapi.DefaultOrganization
+	server.AddAuditLog(org, "db-create", "one", nil)
+	server.AddAuditLog(org, "db-delete", "two", nil)
+	opts := AuditLogExportOptions{
+		Format: AuditLogJSONL,
+		Filter: AuditLogFilter{Codes: []string{"db-create", "db-delete"}},
+		Cursor: NewFileCursorStore(filepath.Join(t.TempDir(), "cursor.json")),
+	}
+
+	var buf bytes.Buffer
+	n, err := ExportAuditLogs(context.Background(), client.AuditLogs, org, &buf, opts)
+	if err != nil || n != 2 {
+		t.Fatalf("exported %d logs: %v", n, err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); !strings.Contains(lines[0], `"one"`) || !strings.Contains(lines[1], `"two"`) {
+		t.Errorf("expected the logs oldest first, got %q", lines)
+	}
+
+	server.AddAuditLog(org, "db-create", "three", nil)
+	buf.Reset()
+	n, err = ExportAuditLogs(context.Background(), client.AuditLogs, org, &buf, opts)
+	if err != nil || n != 1 || !strings.Contains(buf.String(), `"three"`) {
+		t.Errorf("expected only the new log, got %d: %s (%v)", n, buf.String(), err)
+	}
+}
+
+// failingWriter accepts limit bytes, then fails.
+type failingWriter struct {
+	limit int
+	buf   bytes.Buffer
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.limit {
+		return 0, errors.New("disk full")
+	}
+	return w.buf.Write(p)
+}
+
+func TestAuditLogsExportPartialWrite(t *testing.T) {
+	client, server := newTestClient(t)
+	stopClock(server, testStart)
+	org := fakeapi.DefaultOrganization
+	server.AddAuditLog(org, "db-create", "one", nil)
+	server.AddAuditLog(org, "db-delete", "two", nil)
+	store := &memoryCursorStore{}
+	opts := AuditLogExportOptions{Format: AuditLogJSONL, Filter: AuditLogFilter{Codes: []string{"db-create", "db-delete"}}, Cursor: store}
+
+	var full bytes.Buffer
+	if _, err := ExportAuditLogs(context.Background(), client.AuditLogs, org, &full, AuditLogExportOptions{Format: opts.Format, Filter: opts.Filter}); err != nil {
+		t.Fatal(err)
+	}
+	first := strings.Index(full.String(), "\n") + 1
+	w := &failingWriter{limit: first}
+	n, err := ExportAuditLogs(context.Background(), client.AuditLogs, org, w, opts)
+	if err == nil || n != 1 {
+		t.Fatalf("expected 1 log written and an error, got %d, %v", n, err)
+	}
+	if store.cursor.IsZero() {
+		t.Fatal("expected the cursor to be saved after the written log")
+	}
+
+	var buf bytes.Buffer
+	n, err = ExportAuditLogs(context.Background(), client.AuditLogs, org, &buf, opts)
+	if err != nil || n != 1 || !strings.Contains(buf.String(), `"two"`) {
+		t.Errorf("expected the export to resume after the written log, got %d: %s (%v)", n, buf.String(), err)
+	}
+}
+
+func TestAuditLogsExportAdvancesPastFilteredLogs(t *testing.T) {
+	client, server := newTestClient(t)
+	addAuditLogs(server, "db-delete", "db-create", "db-create")
+	store := &memoryCursorStore{}
+	opts := AuditLogExportOptions{Format: AuditLogJSONL, Filter: AuditLogFilter{Codes: []string{"db-delete"}, PageSize: 1}, Cursor: store}
+
+	var buf bytes.Buffer
+	n, err := ExportAuditLogs(context.Background(), client.AuditLogs, fakeapi.DefaultOrganization, &buf, opts)
+	if err != nil || n != 1 || !strings.Contains(buf.String(), `"db-delete"`) {
+		t.Fatalf("expected only the matching log, got %d: %s (%v)", n, buf.String(), err)
+	}
+	if newest := testStart.Add(2 * time.Hour); !store.cursor.CreatedAt.Equal(newest) {
+		t.Errorf("expected the cursor past the newest log at %v, got %+v", newest, store.cursor)
+	}
+}
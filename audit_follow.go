@@ -0,0 +1,344 @@
+package turso
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultFollowInterval = 30 * time.Second
+
+// AuditLogCursor marks the newest audit logs a follower delivered: their
+// timestamp and the hashes of the logs sharing it, since several logs can
+// be created within the same second. Before any log was delivered it holds
+// the time of the last poll and no hashes.
+type AuditLogCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	Hashes    []string  `json:"hashes"`
+}
+
+// IsZero reports whether the follower has not polled yet.
+func (c AuditLogCursor) IsZero() bool {
+	return c.CreatedAt.IsZero()
+}
+
+func (c AuditLogCursor) delivered(createdAt time.Time, hash string) bool {
+	if createdAt.Before(c.CreatedAt) {
+		return true
+	}
+	return createdAt.Equal(c.CreatedAt) && containsString(c.Hashes, hash)
+}
+
+func (c AuditLogCursor) advance(createdAt time.Time, hash string) AuditLogCursor {
+	if createdAt.After(c.CreatedAt) {
+		return AuditLogCursor{CreatedAt: createdAt, Hashes: []string{hash}}
+	}
+	hashes := append(append([]string(nil), c.Hashes...), hash)
+	return AuditLogCursor{CreatedAt: c.CreatedAt, Hashes: hashes}
+}
+
+// AuditLogCursorStore persists the cursor of a follower so that it resumes
+// where it stopped after a restart.
+type AuditLogCursorStore interface {
+	// Load returns the stored cursor, or a zero cursor when none is stored.
+	Load() (AuditLogCursor, error)
+	Save(cursor AuditLogCursor) error
+}
+
+// FileCursorStore stores a cursor as JSON in a file.
+type FileCursorStore struct {
+	path string
+}
+
+var _ AuditLogCursorStore = (*FileCursorStore)(nil)
+
+func NewFileCursorStore(path string) *FileCursorStore {
+	return &FileCursorStore{path: path}
+}
+
+func (s *FileCursorStore) Load() (AuditLogCursor, error) {
+	var cursor AuditLogCursor
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cursor, nil
+	}
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, fmt.Errorf("decoding audit log cursor %s: %w", s.path, err)
+	}
+	return cursor, nil
+}
+
+// Save replaces the file atomically, so that a crash never leaves a
+// partial cursor behind.
+func (s *FileCursorStore) Save(cursor AuditLogCursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+type memoryCursorStore struct {
+	cursor AuditLogCursor
+}
+
+func (s *memoryCursorStore) Load() (AuditLogCursor, error) {
+	return s.cursor, nil
+}
+
+func (s *memoryCursorStore) Save(cursor AuditLogCursor) error {
+	s.cursor = cursor
+	return nil
+}
+
+// AuditLogFollowOptions configures FollowAuditLogs.
+type AuditLogFollowOptions struct {
+	// Interval between polls; 30 seconds when zero.
+	Interval time.Duration
+	// Filter selects the delivered logs. The cursor still advances past the
+	// logs it skips, so that a poll only reads the logs created since the
+	// previous one.
+	Filter AuditLogFilter
+	// Cursor persists the position of the follower; when nil it is only kept
+	// in memory.
+	Cursor AuditLogCursorStore
+	// Backfill delivers the logs created before the first poll when no
+	// cursor is stored. Otherwise the cursor is positioned on the newest
+	// page of logs and only later logs are delivered.
+	Backfill bool
+}
+
+// FollowAuditLogs polls the audit logs of an organization and calls handle
+// with each new event, oldest first, saving the cursor after every event.
+// It returns when ctx is done, or when a poll, handle or the cursor store
+// fails; a later call with the same cursor store resumes without replaying
+// events.
+func FollowAuditLogs(ctx context.Context, service AuditLogsService, orgSlug string, opts AuditLogFollowOptions, handle func(AuditLogEvent) error) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultFollowInterval
+	}
+	store := opts.Cursor
+	if store == nil {
+		store = &memoryCursorStore{}
+	}
+	cursor, err := store.Load()
+	if err != nil {
+		return err
+	}
+	delay := time.Duration(0)
+	if cursor.IsZero() && !opts.Backfill {
+		if cursor, err = latestAuditLogCursor(ctx, service, orgSlug, opts.Filter.PageSize); err != nil {
+			return err
+		}
+		if err := store.Save(cursor); err != nil {
+			return err
+		}
+		delay = interval
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		polledAt := time.Now().UTC().Truncate(time.Second)
+		saved := true
+		cursor, err = walkAuditLogs(ctx, service, orgSlug, opts.Filter.PageSize, opts.Filter.Since, cursor, func(log AuditLog, createdAt time.Time, next AuditLogCursor) error {
+			saved = false
+			if !opts.Filter.match(log, createdAt) {
+				return nil
+			}
+			event, err := log.Event()
+			if err != nil {
+				return err
+			}
+			if err := handle(event); err != nil {
+				return err
+			}
+			saved = true
+			return store.Save(next)
+		})
+		if err != nil {
+			return err
+		}
+		if cursor.IsZero() {
+			// Nothing was found: remember the poll, so that a restart does
+			// not skip the logs created in the meantime.
+			cursor, saved = AuditLogCursor{CreatedAt: polledAt}, false
+		}
+		if !saved {
+			// Logs the filter skipped after the last event: saving past them
+			// keeps the next poll from reading them again.
+			if err := store.Save(cursor); err != nil {
+				return err
+			}
+		}
+		timer.Reset(interval)
+	}
+}
+
+// latestAuditLogCursor positions a cursor on the newest audit logs without
+// walking the history: it reads the first page, and the next ones only
+// while they hold logs sharing the newest timestamp. An organization
+// without logs gets a cursor at the current time.
+func latestAuditLogCursor(ctx context.Context, service AuditLogsService, orgSlug string, pageSize int) (AuditLogCursor, error) {
+	polledAt := time.Now().UTC().Truncate(time.Second)
+	var cursor AuditLogCursor
+	for p := 1; ; p++ {
+		page, err := service.ListPageContext(ctx, orgSlug, AuditLogPageOptions{Page: p, PageSize: pageSize})
+		if err != nil {
+			return cursor, err
+		}
+		for _, log := range page.AuditLogs {
+			createdAt, err := time.Parse(time.RFC3339, log.CreatedAt)
+			if err != nil {
+				return cursor, fmt.Errorf("parsing audit log created_at: %w", err)
+			}
+			if !cursor.IsZero() && createdAt.Before(cursor.CreatedAt) {
+				return cursor, nil
+			}
+			cursor = cursor.advance(createdAt, hashAuditLog(log))
+		}
+		if len(page.AuditLogs) == 0 || page.Pagination.TotalPages <= p {
+			break
+		}
+	}
+	if cursor.IsZero() {
+		cursor.CreatedAt = polledAt
+	}
+	return cursor, nil
+}
+
+// AuditLogEvents runs FollowAuditLogs in a goroutine and delivers the
+// events on a channel. Both channels are closed when it stops; the error
+// channel then yields the error FollowAuditLogs returned.
+func AuditLogEvents(ctx context.Context, service AuditLogsService, orgSlug string, opts AuditLogFollowOptions) (<-chan AuditLogEvent, <-chan error) {
+	events := make(chan AuditLogEvent)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(events)
+		errc <- FollowAuditLogs(ctx, service, orgSlug, opts, func(event AuditLogEvent) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return events, errc
+}
+
+// walkAuditLogs calls visit with each audit log created at or after since
+// that the cursor has not seen, oldest first, along with the cursor
+// advanced past it. It reads the pages from the oldest one holding such
+// logs down to the first, so that only one page is held in memory. It
+// returns the cursor advanced past the logs visited without error.
+func walkAuditLogs(ctx context.Context, service AuditLogsService, orgSlug string, pageSize int, since time.Time, cursor AuditLogCursor, visit func(log AuditLog, createdAt time.Time, next AuditLogCursor) error) (AuditLogCursor, error) {
+	if cursor.CreatedAt.After(since) {
+		since = cursor.CreatedAt
+	}
+	fetch := func(p int) (*AuditLogPage, error) {
+		return service.ListPageContext(ctx, orgSlug, AuditLogPageOptions{Page: p, PageSize: pageSize})
+	}
+	page, err := fetch(1)
+	if err != nil {
+		return cursor, err
+	}
+	total, size := page.Pagination.TotalRows, page.Pagination.PageSize
+	if size <= 0 {
+		size = len(page.AuditLogs)
+	}
+	// Find the oldest page to read: the last one without a bound, otherwise
+	// the first one ending before since.
+	p := 1
+	for len(page.AuditLogs) > 0 && p < page.Pagination.TotalPages {
+		if since.IsZero() {
+			p, page = page.Pagination.TotalPages, nil
+			break
+		}
+		oldest, err := parseAuditLogTime(page.AuditLogs[len(page.AuditLogs)-1])
+		if err != nil {
+			return cursor, err
+		}
+		if oldest.Before(since) {
+			break
+		}
+		p++
+		if page, err = fetch(p); err != nil {
+			return cursor, err
+		}
+	}
+	for p >= 1 {
+		if page == nil {
+			if page, err = fetch(p); err != nil {
+				return cursor, err
+			}
+		}
+		if rows := page.Pagination.TotalRows; rows > total && size > 0 {
+			// Logs created since the previous page was read moved the logs
+			// not visited yet toward the later pages: read again from the
+			// page now holding the oldest of them.
+			p = (p*size+rows-total-1)/size + 1
+			total, page = rows, nil
+			continue
+		}
+		for i := len(page.AuditLogs) - 1; i >= 0; i-- {
+			log := page.AuditLogs[i]
+			createdAt, err := parseAuditLogTime(log)
+			if err != nil {
+				return cursor, err
+			}
+			hash := hashAuditLog(log)
+			if createdAt.Before(since) || cursor.delivered(createdAt, hash) {
+				continue
+			}
+			next := cursor.advance(createdAt, hash)
+			if err := visit(log, createdAt, next); err != nil {
+				return cursor, err
+			}
+			cursor = next
+		}
+		p, page = p-1, nil
+	}
+	return cursor, nil
+}
+
+func parseAuditLogTime(log AuditLog) (time.Time, error) {
+	createdAt, err := time.Parse(time.RFC3339, log.CreatedAt)
+	if err != nil {
+		return createdAt, fmt.Errorf("parsing audit log created_at: %w", err)
+	}
+	return createdAt, nil
+}
+
+func hashAuditLog(log AuditLog) string {
+	data, _ := json.Marshal(log)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,290 @@
+package turso
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mr-destructive/turso-go/internal/fakeapi"
+)
+
+func receiveEvent(t *testing.T, events <-chan AuditLogEvent, errc <-chan error) AuditLogEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case err := <-errc:
+		t.Fatalf("follower stopped: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	return AuditLogEvent{}
+}
+
+func TestAuditLogsFollow(t *testing.T) {
+	client, server := newTestClient(t)
+	// Every log shares a timestamp, so only the cursor hashes tell them apart.
+	stopClock(server, testStart)
+	org := fakeapi.DefaultOrganization
+	server.AddAuditLog(org, "db-create", "before", nil)
+	store := NewFileCursorStore(filepath.Join(t.TempDir(), "cursor.json"))
+	opts := AuditLogFollowOptions{Interval: 10 * time.Millisecond, Cursor: store}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errc := AuditLogEvents(ctx, client.AuditLogs, org, opts)
+	waitForCursor(t, store)
+	server.AddAuditLog(org, "db-delete", "first", nil)
+	if event := receiveEvent(t, events, errc); event.Message != "first" {
+		t.Errorf("expected the first new event, got %+v", event)
+	}
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	server.AddAuditLog(org, "member-add", "while stopped", nil)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	events, errc = AuditLogEvents(ctx, client.AuditLogs, org, opts)
+	if event := receiveEvent(t, events, errc); event.Message != "while stopped" {
+		t.Errorf("expected the event created while stopped, got %+v", event)
+	}
+	server.AddAuditLog(org, "member-remove", "second", nil)
+	if event := receiveEvent(t, events, errc); event.Message != "second" {
+		t.Errorf("expected the second new event, got %+v", event)
+	}
+}
+
+// auditLogPages serves canned pages and records the pages requested.
+type auditLogPages struct {
+	AuditLogsService
+	pages []AuditLogPage
+	read  []int
+}
+
+func (s *auditLogPages) ListPageContext(ctx context.Context, orgSlug string, opts AuditLogPageOptions) (*AuditLogPage, error) {
+	s.read = append(s.read, opts.Page)
+	if opts.Page > len(s.pages) {
+		return &AuditLogPage{}, nil
+	}
+	return &s.pages[opts.Page-1], nil
+}
+
+// cancelOnSave cancels the follower once it saved its first cursor.
+type cancelOnSave struct {
+	memoryCursorStore
+	cancel context.CancelFunc
+}
+
+func (s *cancelOnSave) Save(cursor AuditLogCursor) error {
+	s.cancel()
+	return s.memoryCursorStore.Save(cursor)
+}
+
+func TestAuditLogsFollowPositionsOnNewestPage(t *testing.T) {
+	service := &auditLogPages{pages: []AuditLogPage{
+		{
+			AuditLogs: []AuditLog{
+				{Code: "c", CreatedAt: "2024-01-01T00:00:02Z"},
+				{Code: "b", CreatedAt: "2024-01-01T00:00:02Z"},
+			},
+			Pagination: Pagination{Page: 1, TotalPages: 50},
+		},
+		{
+			AuditLogs: []AuditLog{
+				{Code: "a", CreatedAt: "2024-01-01T00:00:02Z"},
+				{Code: "old", CreatedAt: "2024-01-01T00:00:01Z"},
+			},
+			Pagination: Pagination{Page: 2, TotalPages: 50},
+		},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := &cancelOnSave{cancel: cancel}
+	err := FollowAuditLogs(ctx, service, "org", AuditLogFollowOptions{Cursor: store}, func(event AuditLogEvent) error {
+		t.Errorf("unexpected event %+v", event)
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(service.read) != 2 {
+		t.Errorf("expected only the pages holding the newest logs to be read, got %v", service.read)
+	}
+	cursor := store.cursor
+	if !cursor.CreatedAt.Equal(time.Date(2024, 1, 1, 0, 0, 2, 0, time.UTC)) || len(cursor.Hashes) != 3 {
+		t.Errorf("unexpected cursor %+v", cursor)
+	}
+}
+
+func TestAuditLogsFollowPersistsIdleCursor(t *testing.T) {
+	client, server := newTestClient(t)
+	org := fakeapi.DefaultOrganization
+	store := NewFileCursorStore(filepath.Join(t.TempDir(), "cursor.json"))
+	opts := AuditLogFollowOptions{Interval: 10 * time.Millisecond, Cursor: store}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, errc := AuditLogEvents(ctx, client.AuditLogs, org, opts)
+	waitForCursor(t, store)
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	server.AddAuditLog(org, "db-create", "while stopped", nil)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	events, errc := AuditLogEvents(ctx, client.AuditLogs, org, opts)
+	if event := receiveEvent(t, events, errc); event.Message != "while stopped" {
+		t.Errorf("expected the event created while stopped, got %+v", event)
+	}
+}
+
+func TestAuditLogsFollowBackfill(t *testing.T) {
+	client, server := newTestClient(t)
+	stopClock(server, testStart)
+	org := fakeapi.DefaultOrganization
+	server.AddAuditLog(org, "db-create", "one", nil)
+	server.AddAuditLog(org, "db-delete", "two", nil)
+
+	var messages []string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := FollowAuditLogs(ctx, client.AuditLogs, org, AuditLogFollowOptions{
+		Interval: 10 * time.Millisecond,
+		Backfill: true,
+		Filter:   AuditLogFilter{Codes: []string{"db-create", "db-delete"}},
+	}, func(event AuditLogEvent) error {
+		messages = append(messages, event.Message)
+		if len(messages) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !equalStrings(messages, []string{"one", "two"}) {
+		t.Errorf("expected the logs oldest first, got %v", messages)
+	}
+}
+
+func TestAuditLogsFollowHandlerError(t *testing.T) {
+	client, server := newTestClient(t)
+	stopClock(server, testStart)
+	server.AddAuditLog(fakeapi.DefaultOrganization, "db-create", "one", nil)
+	failure := errors.New("boom")
+	err := FollowAuditLogs(context.Background(), client.AuditLogs, fakeapi.DefaultOrganization, AuditLogFollowOptions{Backfill: true}, func(AuditLogEvent) error {
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("expected the handler error, got %v", err)
+	}
+}
+
+func TestAuditLogsFollowAdvancesPastFilteredLogs(t *testing.T) {
+	newest := AuditLog{Code: "db-create", CreatedAt: "2024-01-01T00:00:03Z"}
+	service := &auditLogPages{pages: []AuditLogPage{
+		{
+			AuditLogs: []AuditLog{
+				newest,
+				{Code: "db-delete", CreatedAt: "2024-01-01T00:00:02Z"},
+			},
+			Pagination: Pagination{Page: 1, PageSize: 2, TotalPages: 2, TotalRows: 3},
+		},
+		{
+			AuditLogs:  []AuditLog{{Code: "db-create", CreatedAt: "2024-01-01T00:00:01Z"}},
+			Pagination: Pagination{Page: 2, PageSize: 2, TotalPages: 2, TotalRows: 3},
+		},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := &memoryCursorStore{}
+	var codes []string
+	err := FollowAuditLogs(ctx, service, "org", AuditLogFollowOptions{
+		Backfill: true,
+		Cursor:   store,
+		Filter:   AuditLogFilter{Codes: []string{"db-delete"}},
+	}, func(event AuditLogEvent) error {
+		codes = append(codes, string(event.Code))
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !equalStrings(codes, []string{"db-delete"}) {
+		t.Errorf("expected only the matching log, got %v", codes)
+	}
+	want := AuditLogCursor{CreatedAt: time.Date(2024, 1, 1, 0, 0, 3, 0, time.UTC), Hashes: []string{hashAuditLog(newest)}}
+	if cursor := store.cursor; !cursor.CreatedAt.Equal(want.CreatedAt) || !equalStrings(cursor.Hashes, want.Hashes) {
+		t.Errorf("expected the cursor past the newest log, got %+v", cursor)
+	}
+}
+
+// addDuringWalk adds an audit log to server just before page is read for
+// the first time.
+type addDuringWalk struct {
+	AuditLogsService
+	server *fakeapi.Server
+	page   int
+	added  bool
+}
+
+func (s *addDuringWalk) ListPageContext(ctx context.Context, orgSlug string, opts AuditLogPageOptions) (*AuditLogPage, error) {
+	if opts.Page == s.page && !s.added {
+		s.added = true
+		stopClock(s.server, testStart.Add(24*time.Hour))
+		s.server.AddAuditLog(orgSlug, "new", "new", nil)
+	}
+	return s.AuditLogsService.ListPageContext(ctx, orgSlug, opts)
+}
+
+func TestWalkAuditLogsShiftedPages(t *testing.T) {
+	client, server := newTestClient(t)
+	addAuditLogs(server, "a", "b", "c", "d", "e")
+	// Pages of two logs: [e d] [c b] [a]. The new log is added once the
+	// walk read the last page, shifting the logs left to read.
+	service := &addDuringWalk{AuditLogsService: client.AuditLogs, server: server, page: 2}
+	var codes []string
+	_, err := walkAuditLogs(context.Background(), service, fakeapi.DefaultOrganization, 2, time.Time{}, AuditLogCursor{}, func(log AuditLog, createdAt time.Time, next AuditLogCursor) error {
+		codes = append(codes, log.Code)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c", "d", "e", "new"}; !equalStrings(codes, want) {
+		t.Errorf("visited %v, want %v", codes, want)
+	}
+}
+
+func TestFileCursorStore(t *testing.T) {
+	store := NewFileCursorStore(filepath.Join(t.TempDir(), "cursor.json"))
+	cursor, err := store.Load()
+	if err != nil || !cursor.IsZero() {
+		t.Fatalf("expected a zero cursor, got %+v, %v", cursor, err)
+	}
+	want := AuditLogCursor{CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Hashes: []string{"a", "b"}}
+	if err := store.Save(want); err != nil {
+		t.Fatal(err)
+	}
+	cursor, err = store.Load()
+	if err != nil || !cursor.CreatedAt.Equal(want.CreatedAt) || !equalStrings(cursor.Hashes, want.Hashes) {
+		t.Errorf("got %+v, %v", cursor, err)
+	}
+}
+
+func waitForCursor(t *testing.T, store AuditLogCursorStore) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cursor, err := store.Load(); err == nil && !cursor.IsZero() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for the cursor")
+}
@@ -1,43 +1,241 @@
 package turso
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 )
 
-type auditLog struct {
-	Author    string                 `json:"author"`
-	Code      string                 `json:"code"`
-	CreatedAt string                 `json:"created_at"`
-	Data      map[string]interface{} `json:"data"`
-	Message   string                 `json:"message"`
-	Origin    string                 `json:"origin"`
+type AuditLog struct {
+	Author    string `json:"author"`
+	Code      string `json:"code"`
+	CreatedAt string `json:"created_at"`
+	// Data is the JSON object sent by the API, kept as is; DataMap and
+	// Event decode it.
+	Data    json.RawMessage `json:"data"`
+	Message string          `json:"message"`
+	Origin  string          `json:"origin"`
 }
 
-type pagination struct {
+// DataMap decodes Data into a map. Numbers are decoded as json.Number so
+// that large integers keep their precision; a log without data yields a
+// nil map.
+func (l AuditLog) DataMap() (map[string]interface{}, error) {
+	var data map[string]interface{}
+	if len(l.Data) == 0 {
+		return data, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(l.Data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("decoding %s audit log data: %w", l.Code, err)
+	}
+	return data, nil
+}
+
+type Pagination struct {
 	Page       int `json:"page"`
 	PageSize   int `json:"page_size"`
 	TotalPages int `json:"total_pages"`
 	TotalRows  int `json:"total_rows"`
 }
 
+// AuditLogPage is one page of the audit logs of an organization, newest
+// first.
+type AuditLogPage struct {
+	AuditLogs  []AuditLog `json:"audit_logs"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// AuditLogPageOptions selects a page of audit logs. Zero values use the
+// API defaults.
+type AuditLogPageOptions struct {
+	Page     int
+	PageSize int
+}
+
 type AuditLogs struct {
-	client     *client
-	AuditLogs  []auditLog `json:"audit_logs"`
-	Pagination pagination `json:"pagination"`
+	client *client
+}
+
+// AuditLogsService reads the audit logs of an organization. Iterating,
+// following and exporting them are functions built on a service:
+// IterateAuditLogs, FollowAuditLogs, AuditLogEvents and ExportAuditLogs.
+type AuditLogsService interface {
+	List(orgSlug string) (*AuditLogPage, error)
+	ListContext(ctx context.Context, orgSlug string) (*AuditLogPage, error)
+	ListPage(orgSlug string, opts AuditLogPageOptions) (*AuditLogPage, error)
+	ListPageContext(ctx context.Context, orgSlug string, opts AuditLogPageOptions) (*AuditLogPage, error)
+}
+
+var _ AuditLogsService = (*AuditLogs)(nil)
+
+// List returns the first page of audit logs.
+func (t *AuditLogs) List(orgSlug string) (*AuditLogPage, error) {
+	return t.ListContext(context.Background(), orgSlug)
 }
 
-func (t *AuditLogs) List(orgSlug string) (*AuditLogs, error) {
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/audit-logs", tursoBaseURL, orgSlug)
-	resp, err := t.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+func (t *AuditLogs) ListContext(ctx context.Context, orgSlug string) (*AuditLogPage, error) {
+	return t.ListPageContext(ctx, orgSlug, AuditLogPageOptions{})
+}
+
+func (t *AuditLogs) ListPage(orgSlug string, opts AuditLogPageOptions) (*AuditLogPage, error) {
+	return t.ListPageContext(context.Background(), orgSlug, opts)
+}
+
+func (t *AuditLogs) ListPageContext(ctx context.Context, orgSlug string, opts AuditLogPageOptions) (*AuditLogPage, error) {
+	if orgSlug == "" {
+		return nil, fmt.Errorf("organization slug is required")
+	}
+	endpoint := t.client.endpoint("/v1/organizations/%s/audit-logs", orgSlug)
+	query := ""
+	if opts.Page > 0 {
+		query += "&page=" + strconv.Itoa(opts.Page)
+	}
+	if opts.PageSize > 0 {
+		query += "&page_size=" + strconv.Itoa(opts.PageSize)
+	}
+	if query != "" {
+		endpoint += "?" + query[1:]
+	}
+	resp, err := t.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
-	var auditLogs AuditLogs
-	if err := json.NewDecoder(resp.Body).Decode(&auditLogs); err != nil {
+	defer resp.Body.Close()
+	var page AuditLogPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	return &auditLogs, nil
+	return &page, nil
+}
+
+// IterateAuditLogs walks every page of audit logs of service, newest
+// first, yielding the logs matching filter.
+func IterateAuditLogs(ctx context.Context, service AuditLogsService, orgSlug string, filter AuditLogFilter) *AuditLogIterator {
+	return newAuditLogIterator(ctx, filter, func(ctx context.Context, opts AuditLogPageOptions) (*AuditLogPage, error) {
+		return service.ListPageContext(ctx, orgSlug, opts)
+	})
+}
+
+// AuditLogFilter selects the audit logs yielded by an AuditLogIterator.
+// PageSize is sent to the API; the other fields are applied by the client
+// and empty fields match every log.
+type AuditLogFilter struct {
+	PageSize int
+	Codes    []string
+	Authors  []string
+	Origins  []string
+	// Since and Until bound CreatedAt, inclusively.
+	Since time.Time
+	Until time.Time
+}
+
+func (f AuditLogFilter) match(log AuditLog, createdAt time.Time) bool {
+	if !f.Since.IsZero() && createdAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && createdAt.After(f.Until) {
+		return false
+	}
+	return matchAny(f.Codes, log.Code) && matchAny(f.Authors, log.Author) && matchAny(f.Origins, log.Origin)
+}
+
+func matchAny(values []string, value string) bool {
+	return len(values) == 0 || containsString(values, value)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditLogIterator walks pages of audit logs:
+//
+//	it := turso.IterateAuditLogs(ctx, client.AuditLogs, "org_slug", turso.AuditLogFilter{Codes: []string{"db-delete"}})
+//	for it.Next() {
+//		log := it.AuditLog()
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle the error
+//	}
+type AuditLogIterator struct {
+	ctx    context.Context
+	filter AuditLogFilter
+	fetch  func(ctx context.Context, opts AuditLogPageOptions) (*AuditLogPage, error)
+
+	page    int
+	last    bool
+	buffer  []AuditLog
+	current AuditLog
+	err     error
+}
+
+// newAuditLogIterator returns an iterator over the pages returned by fetch.
+func newAuditLogIterator(ctx context.Context, filter AuditLogFilter, fetch func(ctx context.Context, opts AuditLogPageOptions) (*AuditLogPage, error)) *AuditLogIterator {
+	return &AuditLogIterator{ctx: ctx, filter: filter, fetch: fetch}
+}
+
+// Next advances to the next matching log. It returns false when every page
+// has been read, the context is done or a request failed.
+func (it *AuditLogIterator) Next() bool {
+	for it.err == nil {
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		if len(it.buffer) == 0 {
+			if it.last {
+				return false
+			}
+			it.fetchPage()
+			continue
+		}
+		log := it.buffer[0]
+		it.buffer = it.buffer[1:]
+		createdAt, err := time.Parse(time.RFC3339, log.CreatedAt)
+		if err != nil && (!it.filter.Since.IsZero() || !it.filter.Until.IsZero()) {
+			it.err = fmt.Errorf("parsing audit log created_at: %w", err)
+			return false
+		}
+		if !it.filter.Since.IsZero() && createdAt.Before(it.filter.Since) {
+			// Logs are returned newest first, so no later log can match.
+			it.buffer, it.last = nil, true
+			return false
+		}
+		if it.filter.match(log, createdAt) {
+			it.current = log
+			return true
+		}
+	}
+	return false
+}
+
+func (it *AuditLogIterator) fetchPage() {
+	it.page++
+	page, err := it.fetch(it.ctx, AuditLogPageOptions{Page: it.page, PageSize: it.filter.PageSize})
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.buffer = page.AuditLogs
+	it.last = len(page.AuditLogs) == 0 || page.Pagination.TotalPages <= it.page
+}
+
+// AuditLog returns the log Next advanced to.
+func (it *AuditLogIterator) AuditLog() AuditLog {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *AuditLogIterator) Err() error {
+	return it.err
 }
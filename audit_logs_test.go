@@ -1,7 +1,12 @@
 package turso
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/mr-destructive/turso-go/internal/fakeapi"
 )
 
 func TestAuditLogs(t *testing.T) {
@@ -17,3 +22,105 @@ func TestAuditLogs(t *testing.T) {
 		t.Error("auditLogs should not be nil")
 	}
 }
+
+// addAuditLogs adds a log for each code to the default organization, an
+// hour apart from testStart.
+func addAuditLogs(server *fakeapi.Server, codes ...string) {
+	for i, code := range codes {
+		stopClock(server, testStart.Add(time.Duration(i)*time.Hour))
+		server.AddAuditLog(fakeapi.DefaultOrganization, code, code, nil)
+	}
+}
+
+func collectAuditLogs(t *testing.T, it *AuditLogIterator) []string {
+	t.Helper()
+	var codes []string
+	for it.Next() {
+		codes = append(codes, it.AuditLog().Code)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return codes
+}
+
+func TestAuditLogsListPage(t *testing.T) {
+	client, server := newTestClient(t)
+	addAuditLogs(server, "a", "b", "c", "d", "e")
+	page, err := client.AuditLogs.ListPage(fakeapi.DefaultOrganization, AuditLogPageOptions{Page: 2, PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.AuditLogs) != 2 || page.AuditLogs[0].Code != "c" || page.AuditLogs[1].Code != "b" {
+		t.Errorf("unexpected page %+v", page.AuditLogs)
+	}
+	if page.Pagination.Page != 2 || page.Pagination.TotalPages != 3 || page.Pagination.TotalRows != 5 {
+		t.Errorf("unexpected pagination %+v", page.Pagination)
+	}
+}
+
+func TestAuditLogIterator(t *testing.T) {
+	client, server := newTestClient(t)
+	addAuditLogs(server, "db-create", "db-delete", "db-create", "group-create", "db-create")
+	start := testStart
+	ctx := context.Background()
+
+	codes := collectAuditLogs(t, IterateAuditLogs(ctx, client.AuditLogs, fakeapi.DefaultOrganization, AuditLogFilter{PageSize: 2}))
+	if want := []string{"db-create", "group-create", "db-create", "db-delete", "db-create"}; !equalStrings(codes, want) {
+		t.Errorf("got %v, want %v", codes, want)
+	}
+
+	codes = collectAuditLogs(t, IterateAuditLogs(ctx, client.AuditLogs, fakeapi.DefaultOrganization, AuditLogFilter{
+		PageSize: 2,
+		Codes:    []string{"db-create"},
+		Since:    start.Add(time.Hour),
+		Until:    start.Add(3 * time.Hour),
+	}))
+	if want := []string{"db-create"}; !equalStrings(codes, want) {
+		t.Errorf("got %v, want %v", codes, want)
+	}
+
+	codes = collectAuditLogs(t, IterateAuditLogs(ctx, client.AuditLogs, fakeapi.DefaultOrganization, AuditLogFilter{Origins: []string{"cli"}}))
+	if len(codes) != 0 {
+		t.Errorf("expected no logs, got %v", codes)
+	}
+}
+
+func TestAuditLogIteratorStopsAtSince(t *testing.T) {
+	var pages []int
+	fetch := func(ctx context.Context, opts AuditLogPageOptions) (*AuditLogPage, error) {
+		pages = append(pages, opts.Page)
+		return &AuditLogPage{
+			AuditLogs: []AuditLog{
+				{Code: "new", CreatedAt: "2024-01-02T00:00:00Z"},
+				{Code: "old", CreatedAt: "2023-12-31T00:00:00Z"},
+			},
+			Pagination: Pagination{Page: opts.Page, TotalPages: 10},
+		}, nil
+	}
+	it := newAuditLogIterator(context.Background(), AuditLogFilter{Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, fetch)
+	codes := collectAuditLogs(t, it)
+	if !equalStrings(codes, []string{"new"}) || len(pages) != 1 {
+		t.Errorf("got logs %v from pages %v", codes, pages)
+	}
+}
+
+func TestAuditLogIteratorErrors(t *testing.T) {
+	failure := errors.New("boom")
+	it := newAuditLogIterator(context.Background(), AuditLogFilter{}, func(ctx context.Context, opts AuditLogPageOptions) (*AuditLogPage, error) {
+		return nil, failure
+	})
+	if it.Next() || !errors.Is(it.Err(), failure) {
+		t.Errorf("expected the fetch error, got %v", it.Err())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = newAuditLogIterator(ctx, AuditLogFilter{}, func(ctx context.Context, opts AuditLogPageOptions) (*AuditLogPage, error) {
+		t.Error("fetch called with a canceled context")
+		return nil, nil
+	})
+	if it.Next() || !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", it.Err())
+	}
+}
@@ -1,6 +1,7 @@
 package turso
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,20 @@ type Tokens struct {
 	client *client
 }
 
+// TokensService manages the API tokens of the authenticated user.
+type TokensService interface {
+	List() (*TokenList, error)
+	ListContext(ctx context.Context) (*TokenList, error)
+	Mint(name string) (*Token, error)
+	MintContext(ctx context.Context, name string) (*Token, error)
+	Revoke(tokenName string) error
+	RevokeContext(ctx context.Context, tokenName string) error
+	Validate() (*TokenValidation, error)
+	ValidateContext(ctx context.Context) (*TokenValidation, error)
+}
+
+var _ TokensService = (*Tokens)(nil)
+
 type Token struct {
 	Name  string `json:"name"`
 	Id    string `json:"id"`
@@ -21,13 +36,29 @@ type TokenList struct {
 	Tokens []Token `json:"tokens"`
 }
 
-type tokenValidate struct {
-	Expiration time.Duration `json:"exp"`
+// TokenValidation is the result of Tokens.Validate.
+type TokenValidation struct {
+	// Expiration is when the API token expires, in Unix seconds, or -1 for
+	// tokens that never expire.
+	Expiration int64 `json:"exp"`
+}
+
+// ExpiresAt returns when the API token expires, or the zero time for
+// tokens that never expire.
+func (v TokenValidation) ExpiresAt() time.Time {
+	if v.Expiration <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(v.Expiration, 0)
 }
 
 func (t *Tokens) List() (*TokenList, error) {
-	endpoint := fmt.Sprintf("%s/v1/auth/api-tokens", tursoBaseURL)
-	resp, err := t.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	return t.ListContext(context.Background())
+}
+
+func (t *Tokens) ListContext(ctx context.Context) (*TokenList, error) {
+	endpoint := t.client.endpoint("/v1/auth/api-tokens")
+	resp, err := t.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -40,11 +71,15 @@ func (t *Tokens) List() (*TokenList, error) {
 }
 
 func (t *Tokens) Mint(name string) (*Token, error) {
+	return t.MintContext(context.Background(), name)
+}
+
+func (t *Tokens) MintContext(ctx context.Context, name string) (*Token, error) {
 	if name == "" {
 		return nil, fmt.Errorf("token name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/auth/api-tokens/%s", tursoBaseURL, name)
-	resp, err := t.client.tursoAPIrequest(endpoint, http.MethodPost, nil)
+	endpoint := t.client.endpoint("/v1/auth/api-tokens/%s", name)
+	resp, err := t.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPost, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -57,11 +92,15 @@ func (t *Tokens) Mint(name string) (*Token, error) {
 }
 
 func (t *Tokens) Revoke(tokenName string) error {
+	return t.RevokeContext(context.Background(), tokenName)
+}
+
+func (t *Tokens) RevokeContext(ctx context.Context, tokenName string) error {
 	if tokenName == "" {
 		return fmt.Errorf("token name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/auth/api-tokens/%s", tursoBaseURL, tokenName)
-	resp, err := t.client.tursoAPIrequest(endpoint, http.MethodDelete, nil)
+	endpoint := t.client.endpoint("/v1/auth/api-tokens/%s", tokenName)
+	resp, err := t.client.tursoAPIrequestContext(ctx, endpoint, http.MethodDelete, nil)
 	if err != nil {
 		return err
 	}
@@ -69,16 +108,20 @@ func (t *Tokens) Revoke(tokenName string) error {
 	return nil
 }
 
-func (t *Tokens) Validate() (*tokenValidate, error) {
-	endpoint := fmt.Sprintf("%s/v1/auth/validate", tursoBaseURL)
-	resp, err := t.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+func (t *Tokens) Validate() (*TokenValidation, error) {
+	return t.ValidateContext(context.Background())
+}
+
+func (t *Tokens) ValidateContext(ctx context.Context) (*TokenValidation, error) {
+	endpoint := t.client.endpoint("/v1/auth/validate")
+	resp, err := t.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
-	var tokenValidate tokenValidate
-	if err := json.NewDecoder(resp.Body).Decode(&tokenValidate); err != nil {
+	var tokenValidation TokenValidation
+	if err := json.NewDecoder(resp.Body).Decode(&tokenValidation); err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	return &tokenValidate, nil
+	return &tokenValidation, nil
 }
@@ -2,6 +2,7 @@ package turso
 
 import (
 	"testing"
+	"time"
 )
 
 func TestAuthTokens(t *testing.T) {
@@ -31,3 +32,17 @@ func TestAuthTokenValidate(t *testing.T) {
 		t.Error("organizations should not be nil")
 	}
 }
+
+func TestTokenValidationExpiration(t *testing.T) {
+	client, _ := newRecordingClient(t, `{"exp":1735689600}`)
+	validation, err := client.Tokens.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validation.Expiration != 1735689600 || !validation.ExpiresAt().Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected expiration %d (%s)", validation.Expiration, validation.ExpiresAt())
+	}
+	if !(TokenValidation{Expiration: -1}).ExpiresAt().IsZero() {
+		t.Error("a token without expiration should expire at the zero time")
+	}
+}
@@ -1,31 +1,43 @@
 package turso
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
+// Client exposes the Turso platform API through one service per resource.
+// The services are interfaces so they can be replaced by fakes in tests.
 type Client struct {
-	client client
-	Tokens
-	Organizations
-	Locations
-	AuditLogs
+	client        client
+	Tokens        TokensService
+	Organizations OrganizationsService
+	Locations     LocationsService
+	AuditLogs     AuditLogsService
 }
 
 type client struct {
-	baseURL  string
-	apiToken string
-	api      *http.Client
+	baseURL   string
+	regionURL string
+	apiToken  string
+	userAgent string
+	retry     RetryPolicy
+	api       *http.Client
+	// transport and timeout are set by WithTransport and WithTimeout, and
+	// applied to api once every option has run.
+	transport http.RoundTripper
+	timeout   *time.Duration
 }
 
 const tursoBaseURL = "https://api.turso.tech"
 const tursoBaseURLRegion = "https://region.turso.io"
+const defaultUserAgent = "turso-go"
 
-func NewClient(baseURL, apiToken string) (*Client, error) {
+func NewClient(baseURL, apiToken string, opts ...Option) (*Client, error) {
 	if baseURL == "" {
 		baseURL = tursoBaseURL
 	}
@@ -33,46 +45,123 @@ func NewClient(baseURL, apiToken string) (*Client, error) {
 		return nil, fmt.Errorf("apiToken is required")
 	}
 	connection := &client{
-		baseURL:  baseURL,
-		apiToken: apiToken,
-		api:      &http.Client{},
+		baseURL:   baseURL,
+		regionURL: tursoBaseURLRegion,
+		apiToken:  apiToken,
+		userAgent: defaultUserAgent,
+		api:       &http.Client{},
 	}
+	for _, opt := range opts {
+		opt(connection)
+	}
+	connection.applyHTTPOptions()
 	client := &Client{
 		client: *connection,
 	}
-	client.Tokens = Tokens{
+	client.Tokens = &Tokens{
 		client: connection,
 	}
-	client.Organizations = Organizations{
+	client.Organizations = &Organizations{
 		client: connection,
 	}
-	client.Locations = Locations{
+	client.Locations = &Locations{
 		client: connection,
 	}
-	client.AuditLogs = AuditLogs{
+	client.AuditLogs = &AuditLogs{
 		client: connection,
 	}
 	return client, nil
 }
 
+// endpoint builds a URL on the configured base URL. The format is a path
+// whose %s verbs are replaced by the path-escaped segments.
+func (client *client) endpoint(format string, segments ...string) string {
+	return buildURL(client.baseURL, format, segments...)
+}
+
+// regionEndpoint builds the URL used to look up the closest region.
+func (client *client) regionEndpoint() string {
+	return buildURL(client.regionURL, "")
+}
+
+func buildURL(base, format string, segments ...string) string {
+	args := make([]interface{}, len(segments))
+	for i, segment := range segments {
+		args[i] = url.PathEscape(segment)
+	}
+	return strings.TrimRight(base, "/") + fmt.Sprintf(format, args...)
+}
+
 func (client *client) tursoAPIrequest(endpoint string, method string, body interface{}) (*http.Response, error) {
+	return client.tursoAPIrequestContext(context.Background(), endpoint, method, body)
+}
+
+func (client *client) tursoAPIrequestContext(ctx context.Context, endpoint string, method string, body interface{}) (*http.Response, error) {
 	endpointURL, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, err
 	}
-	headers := http.Header{}
-	headers.Set("Authorization", fmt.Sprintf("Bearer %s", client.apiToken))
-	req := &http.Request{
-		Method: method,
-		URL:    endpointURL,
-		Header: headers,
+	encoded, err := encodeRequestBody(body)
+	if err != nil {
+		return nil, err
 	}
-	if body != nil {
-		req.Body = io.NopCloser(bytes.NewBuffer([]byte(body.(string))))
+	for attempt := 1; ; attempt++ {
+		req, err := client.newRequest(ctx, method, endpointURL, encoded)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.api.Do(req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+			return resp, nil
+		}
+		if attempt >= client.retry.MaxAttempts || !encoded.replayable() || !client.retry.allowed(method) || !client.retry.retryable(ctx, resp, err) || !client.retry.waitAllowed(resp) {
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			return nil, newAPIError(req, resp)
+		}
+		event := RetryEvent{
+			Method:  method,
+			Path:    endpointURL.Path,
+			Attempt: attempt,
+			Err:     err,
+			Wait:    client.retry.backoff(attempt, resp),
+		}
+		if resp != nil {
+			event.StatusCode = resp.StatusCode
+			event.Err = newAPIError(req, resp)
+			resp.Body.Close()
+		}
+		if client.retry.OnRetry != nil {
+			client.retry.OnRetry(event)
+		}
+		if err := sleepContext(ctx, event.Wait); err != nil {
+			return nil, err
+		}
 	}
-	resp, err := client.api.Do(req)
+}
+
+func (client *client) newRequest(ctx context.Context, method string, endpointURL *url.URL, body *requestBody) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpointURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-	return resp, nil
+	if body != nil {
+		reader, err := body.reader()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(reader)
+		req.ContentLength = body.length
+		if body.length == 0 {
+			req.Body = http.NoBody
+		}
+		req.Header.Set("Content-Type", body.contentType)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", client.apiToken))
+	if client.userAgent != "" {
+		req.Header.Set("User-Agent", client.userAgent)
+	}
+	return req, nil
 }
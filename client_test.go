@@ -1,15 +1,52 @@
 package turso
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+
+	"github.com/mr-destructive/turso-go/internal/fakeapi"
 )
 
+// fakeServer stands in for the Turso API when TURSO_AUTH_TOKEN is not set.
+var fakeServer *fakeapi.Server
+
+func TestMain(m *testing.M) {
+	if os.Getenv("TURSO_AUTH_TOKEN") == "" {
+		fakeServer = fakeapi.NewServer()
+		if org_name == "" {
+			org_name = fakeapi.DefaultOrganization
+		}
+		if db_name == "" {
+			db_name = "turstest-db"
+		}
+		fakeServer.AddDatabase(org_name, fakeapi.DefaultGroup, db_name)
+		if instance_name == "" {
+			instance_name = fakeapi.DefaultLocation
+		} else {
+			fakeServer.AddInstance(org_name, db_name, instance_name, fakeapi.DefaultLocation)
+		}
+	}
+	code := m.Run()
+	if fakeServer != nil {
+		fakeServer.Close()
+	}
+	os.Exit(code)
+}
+
 func newClient() (*Client, error) {
 	baseURL := ""
 	apiToken := os.Getenv("TURSO_AUTH_TOKEN")
-	client, err := NewClient(baseURL, apiToken)
+	var opts []Option
+	if fakeServer != nil {
+		baseURL = fakeServer.URL
+		apiToken = fakeServer.Token
+		opts = append(opts, WithRegionURL(fakeServer.RegionURL()))
+	}
+	client, err := NewClient(baseURL, apiToken, opts...)
 	if err != nil || client == nil {
 		return nil, err
 	}
@@ -35,3 +72,45 @@ func TestNewClient(t *testing.T) {
 		t.Errorf("Failed to create api connection")
 	}
 }
+
+func TestTursoAPIrequestContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	client, err := NewClient(server.URL, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.client.tursoAPIrequestContext(ctx, server.URL, http.MethodGet, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestEndpointsUseConfiguredURLs(t *testing.T) {
+	server, recorded := newRecordingServer(t, `{}`)
+	client, err := NewClient(server.URL+"/api/", "token", WithRegionURL(server.URL+"/region"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		call func() error
+		path string
+	}{
+		{func() error { _, err := client.Locations.List(); return err }, "/api/v1/locations"},
+		{func() error { _, err := client.Locations.Closest(); return err }, "/region"},
+		{func() error { _, err := client.Organizations.Database("my org", "db/1"); return err }, "/api/v1/organizations/my%20org/databases/db%2F1"},
+		{func() error { return client.Organizations.RotateGroupTokens("org", "group?x") }, "/api/v1/organizations/org/groups/group%3Fx/auth/rotate"},
+	}
+	for _, test := range tests {
+		if err := test.call(); err != nil {
+			t.Fatal(err)
+		}
+		if recorded.path != test.path {
+			t.Errorf("requested %s, want %s", recorded.path, test.path)
+		}
+	}
+}
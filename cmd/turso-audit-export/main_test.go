@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mr-destructive/turso-go/turstest"
+)
+
+func TestRun(t *testing.T) {
+	server := turstest.NewServer()
+	defer server.Close()
+	server.AddAuditLog(turstest.DefaultOrganization, "db-delete", "deleted database old", nil)
+	t.Setenv("TURSO_AUTH_TOKEN", server.Token)
+	cursor := filepath.Join(t.TempDir(), "cursor.json")
+	args := []string{"-org", turstest.DefaultOrganization, "-format", "cef", "-codes", "db-delete", "-cursor", cursor, "-base-url", server.URL}
+
+	var out bytes.Buffer
+	if err := run(args, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "CEF:0|Turso|Platform API|1|db-delete|deleted database old|") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	out.Reset()
+	if err := run(args, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected the cursor to skip exported logs, got %q", out.String())
+	}
+}
+
+func TestRunRequiresOrg(t *testing.T) {
+	t.Setenv("TURSO_ORG_NAME", "")
+	if err := run(nil, &bytes.Buffer{}); err == nil {
+		t.Error("expected an error without an organization")
+	}
+}
+
+func TestRunAppendsCSV(t *testing.T) {
+	server := turstest.NewServer()
+	defer server.Close()
+	server.AddAuditLog(turstest.DefaultOrganization, "db-create", "created database one", nil)
+	t.Setenv("TURSO_AUTH_TOKEN", server.Token)
+	dir := t.TempDir()
+	output := filepath.Join(dir, "audit.csv")
+	args := []string{"-org", turstest.DefaultOrganization, "-format", "csv", "-columns", "code,message", "-cursor", filepath.Join(dir, "cursor.json"), "-output", output, "-base-url", server.URL}
+
+	if err := run(args, &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	server.AddAuditLog(turstest.DefaultOrganization, "db-delete", "deleted database one", nil)
+	if err := run(args, &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "code,message\ndb-create,created database one\ndb-delete,deleted database one\n"
+	if string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+}
+
+func TestRunCSVFlattensData(t *testing.T) {
+	server := turstest.NewServer()
+	defer server.Close()
+	server.AddAuditLog(turstest.DefaultOrganization, "db-create", "created database one", map[string]interface{}{"name": "one", "group": "default"})
+	t.Setenv("TURSO_AUTH_TOKEN", server.Token)
+	args := []string{"-org", turstest.DefaultOrganization, "-format", "csv", "-base-url", server.URL}
+
+	var out bytes.Buffer
+	if err := run(args, &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "data.name") || !strings.Contains(lines[1], ",default,") {
+		t.Errorf("expected the data in columns of their own, got %q", lines)
+	}
+}
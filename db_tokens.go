@@ -0,0 +1,270 @@
+package turso
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Authorization is the access level of a database token.
+type Authorization string
+
+const (
+	FullAccess Authorization = "full-access"
+	ReadOnly   Authorization = "read-only"
+)
+
+// NeverExpires mints tokens without an expiration.
+const NeverExpires time.Duration = 0
+
+// MintTokenOptions configures a minted database or group token. The zero
+// value mints a full-access token that never expires.
+type MintTokenOptions struct {
+	// Expiration is the lifetime of the token, in whole seconds, or
+	// NeverExpires.
+	Expiration    time.Duration
+	Authorization Authorization
+	// Permissions grants access beyond the database or group of the token.
+	Permissions *TokenPermissions
+}
+
+// TokenPermissions are the fine-grained permissions of a token.
+type TokenPermissions struct {
+	// ReadAttach lists the databases the token may ATTACH, read-only.
+	ReadAttach []string
+}
+
+type mintTokenBody struct {
+	Permissions struct {
+		ReadAttach struct {
+			Databases []string `json:"databases"`
+		} `json:"read_attach"`
+	} `json:"permissions"`
+}
+
+// body returns the request body carrying the permissions, or nil when
+// there are none.
+func (o MintTokenOptions) body() interface{} {
+	if o.Permissions == nil {
+		return nil
+	}
+	var body mintTokenBody
+	body.Permissions.ReadAttach.Databases = append([]string{}, o.Permissions.ReadAttach...)
+	return body
+}
+
+func (o MintTokenOptions) query() (string, error) {
+	expiration, err := formatExpiration(o.Expiration)
+	if err != nil {
+		return "", err
+	}
+	authorization := o.Authorization
+	if authorization == "" {
+		authorization = FullAccess
+	}
+	if authorization != FullAccess && authorization != ReadOnly {
+		return "", fmt.Errorf("invalid authorization %q", authorization)
+	}
+	values := url.Values{}
+	values.Set("expiration", expiration)
+	values.Set("authorization", string(authorization))
+	return values.Encode(), nil
+}
+
+// formatExpiration writes a duration the way the API expects it, such as
+// "2d3h30m".
+func formatExpiration(d time.Duration) (string, error) {
+	if d == NeverExpires {
+		return "never", nil
+	}
+	if d < time.Second || d%time.Second != 0 {
+		return "", fmt.Errorf("invalid expiration %s: must be a positive number of seconds", d)
+	}
+	var b strings.Builder
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	} {
+		if n := d / unit.size; n > 0 {
+			b.WriteString(strconv.FormatInt(int64(n), 10) + unit.suffix)
+			d -= n * unit.size
+		}
+	}
+	return b.String(), nil
+}
+
+// TokenClaims are the claims of a database or group token.
+type TokenClaims struct {
+	// ExpiresAt is zero for tokens that never expire.
+	ExpiresAt     time.Time
+	IssuedAt      time.Time
+	Authorization Authorization
+	// DatabaseID is set for database tokens and GroupID for group tokens.
+	DatabaseID string
+	GroupID    string
+	// Permissions is nil for tokens without fine-grained permissions.
+	Permissions *TokenPermissions
+}
+
+// Expired reports whether the token has expired at now.
+func (c TokenClaims) Expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Exp int64  `json:"exp"`
+	Iat int64  `json:"iat"`
+	A   string `json:"a"`
+	ID  string `json:"id"`
+	GID string `json:"gid"`
+	P   *struct {
+		ReadAttach *struct {
+			Namespaces []string `json:"ns"`
+		} `json:"roa"`
+	} `json:"p"`
+}
+
+var (
+	// ErrTokenSignature is returned by VerifyToken for tokens not signed
+	// by the given key.
+	ErrTokenSignature = errors.New("invalid token signature")
+	// ErrTokenExpired is returned by VerifyToken for expired tokens.
+	ErrTokenExpired = errors.New("token expired")
+)
+
+type jwtParts struct {
+	header    jwtHeader
+	claims    TokenClaims
+	signed    string
+	signature []byte
+}
+
+func splitToken(jwt string) (*jwtParts, error) {
+	segments := strings.Split(jwt, ".")
+	if len(segments) != 3 {
+		return nil, fmt.Errorf("malformed token: expected 3 parts, got %d", len(segments))
+	}
+	var parts jwtParts
+	header, err := base64.RawURLEncoding.DecodeString(segments[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	if err := json.Unmarshal(header, &parts.header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(segments[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token payload: %w", err)
+	}
+	if parts.claims, err = decodeClaims(payload); err != nil {
+		return nil, err
+	}
+	if parts.signature, err = base64.RawURLEncoding.DecodeString(segments[2]); err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+	parts.signed = segments[0] + "." + segments[1]
+	return &parts, nil
+}
+
+func decodeClaims(payload []byte) (TokenClaims, error) {
+	var raw jwtClaims
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return TokenClaims{}, fmt.Errorf("malformed token claims: %w", err)
+	}
+	claims := TokenClaims{DatabaseID: raw.ID, GroupID: raw.GID}
+	if raw.Exp != 0 {
+		claims.ExpiresAt = time.Unix(raw.Exp, 0).UTC()
+	}
+	if raw.Iat != 0 {
+		claims.IssuedAt = time.Unix(raw.Iat, 0).UTC()
+	}
+	switch raw.A {
+	case "rw", "":
+		claims.Authorization = FullAccess
+	case "ro":
+		claims.Authorization = ReadOnly
+	default:
+		return TokenClaims{}, fmt.Errorf("unknown token access level %q", raw.A)
+	}
+	if raw.P != nil && raw.P.ReadAttach != nil {
+		claims.Permissions = &TokenPermissions{ReadAttach: raw.P.ReadAttach.Namespaces}
+	}
+	return claims, nil
+}
+
+// ParseTokenClaims decodes the claims of a JWT. It does not verify the
+// signature; use VerifyToken for tokens that are not trusted.
+func ParseTokenClaims(jwt string) (TokenClaims, error) {
+	parts, err := splitToken(jwt)
+	if err != nil {
+		return TokenClaims{}, err
+	}
+	return parts.claims, nil
+}
+
+// VerifyToken checks that jwt is an EdDSA token signed by key that has not
+// expired, and returns its claims. It works offline.
+func VerifyToken(jwt string, key ed25519.PublicKey) (TokenClaims, error) {
+	parts, err := splitToken(jwt)
+	if err != nil {
+		return TokenClaims{}, err
+	}
+	if parts.header.Alg != "EdDSA" {
+		return TokenClaims{}, fmt.Errorf("unsupported token algorithm %q", parts.header.Alg)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return TokenClaims{}, fmt.Errorf("invalid Ed25519 public key length %d", len(key))
+	}
+	if !ed25519.Verify(key, []byte(parts.signed), parts.signature) {
+		return TokenClaims{}, ErrTokenSignature
+	}
+	if parts.claims.Expired(time.Now()) {
+		return parts.claims, ErrTokenExpired
+	}
+	return parts.claims, nil
+}
+
+// Verify verifies the token with VerifyToken.
+func (t *JWTToken) Verify(key ed25519.PublicKey) (TokenClaims, error) {
+	return VerifyToken(t.JWT, key)
+}
+
+// ParsePublicKey parses an Ed25519 public key given as a PEM block or as
+// the base64 encoding, standard or URL-safe, of its 32 bytes.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing public key: %w", err)
+		}
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key is a %T, not an Ed25519 key", key)
+		}
+		return edKey, nil
+	}
+	s = strings.TrimSpace(s)
+	for _, encoding := range []*base64.Encoding{base64.RawURLEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.StdEncoding} {
+		if key, err := encoding.DecodeString(s); err == nil && len(key) == ed25519.PublicKeySize {
+			return ed25519.PublicKey(key), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid Ed25519 public key")
+}
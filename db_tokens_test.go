@@ -0,0 +1,236 @@
+package turso
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mr-destructive/turso-go/internal/fakeapi"
+)
+
+func TestFormatExpiration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{NeverExpires, "never"},
+		{time.Second, "1s"},
+		{90 * time.Minute, "1h30m"},
+		{7*24*time.Hour + 3*time.Hour + 5*time.Second, "7d3h5s"},
+	}
+	for _, test := range tests {
+		got, err := formatExpiration(test.duration)
+		if err != nil || got != test.want {
+			t.Errorf("formatExpiration(%s) = %q, %v, want %q", test.duration, got, err, test.want)
+		}
+	}
+	for _, invalid := range []time.Duration{-time.Hour, time.Millisecond, 1500 * time.Millisecond} {
+		if _, err := formatExpiration(invalid); err == nil {
+			t.Errorf("formatExpiration(%s) should fail", invalid)
+		}
+	}
+}
+
+func TestMintTokenOptionsQuery(t *testing.T) {
+	query, err := MintTokenOptions{}.query()
+	if err != nil || query != "authorization=full-access&expiration=never" {
+		t.Errorf("got %q, %v", query, err)
+	}
+	query, err = MintTokenOptions{Expiration: 2 * time.Hour, Authorization: ReadOnly}.query()
+	if err != nil || query != "authorization=read-only&expiration=2h" {
+		t.Errorf("got %q, %v", query, err)
+	}
+	if _, err := (MintTokenOptions{Authorization: "admin"}).query(); err == nil {
+		t.Error("expected an error for an unknown authorization")
+	}
+}
+
+func TestParseTokenClaims(t *testing.T) {
+	// {"alg":"EdDSA","typ":"JWT"}.{"a":"ro","exp":1700003600,"iat":1700000000,"id":"db-id"}
+	jwt := "eyJhbGciOiJFZERTQSIsInR5cCI6IkpXVCJ9.eyJhIjoicm8iLCJleHAiOjE3MDAwMDM2MDAsImlhdCI6MTcwMDAwMDAwMCwiaWQiOiJkYi1pZCJ9.c2ln"
+	claims, err := ParseTokenClaims(jwt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TokenClaims{
+		ExpiresAt:     time.Unix(1700003600, 0).UTC(),
+		IssuedAt:      time.Unix(1700000000, 0).UTC(),
+		Authorization: ReadOnly,
+		DatabaseID:    "db-id",
+	}
+	if claims != want {
+		t.Errorf("got %+v, want %+v", claims, want)
+	}
+	for _, malformed := range []string{"", "a.b", "a.!!!.c", "a.bm90IGpzb24.c"} {
+		if _, err := ParseTokenClaims(malformed); err == nil {
+			t.Errorf("ParseTokenClaims(%q) should fail", malformed)
+		}
+	}
+}
+
+func TestMintToken(t *testing.T) {
+	client, server := newTestClient(t)
+	now := testStart
+	stopClock(server, now)
+	server.AddDatabase(fakeapi.DefaultOrganization, fakeapi.DefaultGroup, "tokens")
+	db, err := client.Organizations.Database(fakeapi.DefaultOrganization, "tokens")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := client.Organizations.MintToken(fakeapi.DefaultOrganization, "tokens", MintTokenOptions{Expiration: 36 * time.Hour, Authorization: ReadOnly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TokenClaims{ExpiresAt: now.Add(36 * time.Hour), IssuedAt: now, Authorization: ReadOnly, DatabaseID: db.DbId}
+	if token.Claims == nil || *token.Claims != want {
+		t.Errorf("got claims %+v, want %+v", token.Claims, want)
+	}
+
+	token, err = client.Organizations.MintToken(fakeapi.DefaultOrganization, "tokens", MintTokenOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !token.Claims.ExpiresAt.IsZero() || token.Claims.Authorization != FullAccess {
+		t.Errorf("expected a full-access token that never expires, got %+v", token.Claims)
+	}
+}
+
+func TestMintTokenUndecodableClaims(t *testing.T) {
+	client, _ := newRecordingClient(t, `{"jwt":"opaque-token"}`)
+	token, err := client.Organizations.MintToken("org", "db", MintTokenOptions{})
+	if err != nil {
+		t.Fatalf("expected the token despite its claims, got %v", err)
+	}
+	if token.JWT != "opaque-token" || token.Claims != nil || token.ClaimsErr == nil {
+		t.Errorf("expected the JWT with nil claims and a claims error, got %+v", token)
+	}
+}
+
+func TestMintGroupToken(t *testing.T) {
+	client, server := newTestClient(t)
+	server.AddDatabase(fakeapi.DefaultOrganization, fakeapi.DefaultGroup, "shared")
+	group, err := client.Organizations.Group(fakeapi.DefaultOrganization, fakeapi.DefaultGroup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := client.Organizations.MintGroupToken(fakeapi.DefaultOrganization, fakeapi.DefaultGroup, MintTokenOptions{
+		Expiration:    time.Hour,
+		Authorization: ReadOnly,
+		Permissions:   &TokenPermissions{ReadAttach: []string{"shared"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Claims.GroupID != group.UUID || token.Claims.DatabaseID != "" || token.Claims.Authorization != ReadOnly {
+		t.Errorf("unexpected claims %+v", token.Claims)
+	}
+	if got := token.Claims.ExpiresAt.Sub(token.Claims.IssuedAt); got != time.Hour {
+		t.Errorf("expected the token to expire after an hour, got %s", got)
+	}
+
+	_, err = client.Organizations.MintGroupToken(fakeapi.DefaultOrganization, fakeapi.DefaultGroup, MintTokenOptions{
+		Permissions: &TokenPermissions{ReadAttach: []string{"missing"}},
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a 400 for an unknown database, got %v", err)
+	}
+	if _, err := client.Organizations.MintGroupToken(fakeapi.DefaultOrganization, "", MintTokenOptions{}); err == nil {
+		t.Error("expected an error without a group name")
+	}
+}
+
+func TestRotateGroupTokens(t *testing.T) {
+	client, recorded := newRecordingClient(t, `{}`)
+	if err := client.Organizations.RotateGroupTokens("org", "tenants"); err != nil {
+		t.Fatal(err)
+	}
+	if recorded.method != http.MethodPost || recorded.path != "/v1/organizations/org/groups/tenants/auth/rotate" {
+		t.Errorf("unexpected request %s %s", recorded.method, recorded.path)
+	}
+
+	recorded.path = ""
+	if err := client.Organizations.InvalidateAllGroupTokens("org", "tenants", "ignored"); err != nil {
+		t.Fatal(err)
+	}
+	if recorded.path != "/v1/organizations/org/groups/tenants/auth/rotate" {
+		t.Errorf("the deprecated method should rotate the tokens, got %s", recorded.path)
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	client, server := newTestClient(t)
+	server.AddDatabase(fakeapi.DefaultOrganization, fakeapi.DefaultGroup, "verified")
+	token, err := client.Organizations.MintGroupToken(fakeapi.DefaultOrganization, fakeapi.DefaultGroup, MintTokenOptions{
+		Expiration:  time.Hour,
+		Permissions: &TokenPermissions{ReadAttach: []string{"verified"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := token.Verify(server.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.GroupID == "" || claims.Permissions == nil || !equalStrings(claims.Permissions.ReadAttach, []string{"verified"}) {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	otherKey, _, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err := VerifyToken(token.JWT, otherKey); !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("expected ErrTokenSignature for another key, got %v", err)
+	}
+	parts := strings.Split(token.JWT, ".")
+	forged := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"a":"rw","gid":"other"}`)) + "." + parts[2]
+	if _, err := VerifyToken(forged, server.PublicKey()); !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("expected ErrTokenSignature for forged claims, got %v", err)
+	}
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+	if _, err := VerifyToken(none, server.PublicKey()); err == nil {
+		t.Error("expected an error for an unsigned token")
+	}
+
+	server.SetClock(func() time.Time { return time.Now().Add(-2 * time.Hour) })
+	expired, err := client.Organizations.MintToken(fakeapi.DefaultOrganization, "verified", MintTokenOptions{Expiration: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expired.Verify(server.PublicKey()); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded := []string{
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		base64.RawURLEncoding.EncodeToString(key),
+		base64.StdEncoding.EncodeToString(key) + "\n",
+	}
+	for _, s := range encoded {
+		parsed, err := ParsePublicKey(s)
+		if err != nil || !parsed.Equal(key) {
+			t.Errorf("ParsePublicKey(%q) = %v, %v", s, parsed, err)
+		}
+	}
+	if _, err := ParsePublicKey("c2hvcnQ"); err == nil {
+		t.Error("expected an error for a short key")
+	}
+}
@@ -0,0 +1,104 @@
+package turso
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize bounds how much of an error response is read.
+const maxErrorBodySize = 64 << 10
+
+// APIError is returned by every method when the Turso API responds with a
+// non-2xx status code.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Method     string
+	Path       string
+	RequestID  string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request id %s)", e.RequestID)
+	}
+	return msg
+}
+
+func newAPIError(req *http.Request, resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		RequestID:  requestID(resp.Header),
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	var errorBody struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &errorBody); err == nil {
+		apiErr.Message = errorBody.Error
+		if apiErr.Message == "" {
+			apiErr.Message = errorBody.Message
+		}
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	return apiErr
+}
+
+func requestID(header http.Header) string {
+	for _, key := range []string{"X-Request-Id", "Fly-Request-Id"} {
+		if id := header.Get(key); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound reports whether err is an APIError with a 404 status.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err is an APIError with a 401 status.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsConflict reports whether err is an APIError with a 409 status.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsRateLimited reports whether err is an APIError with a 429 status.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsQuotaExceeded reports whether err is an APIError caused by the
+// organization exceeding the quotas of its plan.
+func IsQuotaExceeded(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.StatusCode == http.StatusPaymentRequired {
+		return true
+	}
+	return apiErr.StatusCode >= 400 && strings.Contains(strings.ToLower(apiErr.Message), "quota")
+}
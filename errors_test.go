@@ -0,0 +1,62 @@
+package turso
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": "database not found"}`))
+	}))
+	defer server.Close()
+	client, err := NewClient(server.URL, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	database, err := client.Organizations.Database("org", "missing")
+	if database != nil {
+		t.Error("database should be nil on error")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "database not found" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+	if apiErr.Method != http.MethodGet || apiErr.Path != "/v1/organizations/org/databases/missing" {
+		t.Errorf("unexpected request in error %+v", apiErr)
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("unexpected request id %s", apiErr.RequestID)
+	}
+	if !IsNotFound(err) || IsUnauthorized(err) {
+		t.Error("error should only be reported as not found")
+	}
+}
+
+func TestAPIErrorPredicates(t *testing.T) {
+	cases := []struct {
+		err       *APIError
+		predicate func(error) bool
+	}{
+		{&APIError{StatusCode: http.StatusUnauthorized}, IsUnauthorized},
+		{&APIError{StatusCode: http.StatusConflict}, IsConflict},
+		{&APIError{StatusCode: http.StatusTooManyRequests}, IsRateLimited},
+		{&APIError{StatusCode: http.StatusPaymentRequired}, IsQuotaExceeded},
+		{&APIError{StatusCode: http.StatusForbidden, Message: "storage quota exceeded"}, IsQuotaExceeded},
+	}
+	for _, c := range cases {
+		if !c.predicate(c.err) {
+			t.Errorf("predicate should match %+v", c.err)
+		}
+	}
+	if IsNotFound(errors.New("not found")) {
+		t.Error("plain errors should not match")
+	}
+}
@@ -0,0 +1,105 @@
+package turso
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mr-destructive/turso-go/internal/fakeapi"
+)
+
+// The fixtures shared by the tests of this package build on
+// internal/fakeapi, the fake behind turstest.Server, since turstest
+// imports this package.
+
+// testStart is the time the clocks of fake servers are stopped at.
+var testStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestClient starts a fake platform server, closed when the test ends,
+// and returns a client pointed at it, like turstest.NewClient.
+func newTestClient(t *testing.T, opts ...Option) (*Client, *fakeapi.Server) {
+	t.Helper()
+	server := fakeapi.NewServer()
+	t.Cleanup(server.Close)
+	opts = append([]Option{WithRegionURL(server.RegionURL())}, opts...)
+	client, err := NewClient(server.URL, server.Token, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
+// stopClock stops the clock of server at now.
+func stopClock(server *fakeapi.Server, now time.Time) {
+	server.SetClock(func() time.Time { return now })
+}
+
+// recordedRequest is the last request received by a recording server.
+type recordedRequest struct {
+	method string
+	// path is the escaped request path.
+	path          string
+	contentType   string
+	contentLength int64
+	body          string
+	// attempts counts the requests received.
+	attempts int
+	// failures is the number of requests answered with failStatus, and
+	// the retryAfter header or zero, before response is sent.
+	failures   int
+	failStatus int
+	retryAfter string
+}
+
+// newRecordingServer starts a server, closed when the test ends, that
+// records each request and answers it with response.
+func newRecordingServer(t *testing.T, response string) (*httptest.Server, *recordedRequest) {
+	t.Helper()
+	recorded := &recordedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		recorded.method = r.Method
+		recorded.path = r.URL.EscapedPath()
+		recorded.contentType = r.Header.Get("Content-Type")
+		recorded.contentLength = r.ContentLength
+		recorded.body = string(body)
+		recorded.attempts++
+		if recorded.attempts <= recorded.failures {
+			retryAfter := recorded.retryAfter
+			if retryAfter == "" {
+				retryAfter = "0"
+			}
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(recorded.failStatus)
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, recorded
+}
+
+// newRecordingClient returns a client pointed at a new recording server.
+func newRecordingClient(t *testing.T, response string, opts ...Option) (*Client, *recordedRequest) {
+	t.Helper()
+	server, recorded := newRecordingServer(t, response)
+	client, err := NewClient(server.URL, "token", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, recorded
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,299 @@
+// Package hrana runs SQL against Turso databases over the Hrana HTTP
+// pipeline protocol.
+//
+// A Client is built from the hostname of a database and a token minted for
+// it:
+//
+//	db, _ := client.Organizations.Database("org_slug", "my_db")
+//	token, _ := client.Organizations.MintToken("org_slug", "my_db", turso.MintTokenOptions{})
+//	conn, _ := hrana.NewClient(db.Hostname, token.JWT)
+//	result, err := conn.Execute(ctx, "SELECT * FROM users WHERE id = ?", 42)
+//
+// Execute returns all rows at once; Cursor streams them for large results.
+package hrana
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxErrorBodySize bounds how much of an error response is read.
+const maxErrorBodySize = 64 << 10
+
+// Client sends statements to one database. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	token   string
+	version int
+	http    *http.Client
+}
+
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client used for every request.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.http = httpClient
+		}
+	}
+}
+
+// WithProtocolVersion selects the /v2 or /v3 pipeline endpoint. Version 3
+// is the default.
+func WithProtocolVersion(version int) Option {
+	return func(c *Client) {
+		c.version = version
+	}
+}
+
+// NewClient returns a client for the database at host, which is either a
+// hostname such as "my-db-my-org.turso.io" or a libsql://, https:// or
+// http:// URL.
+func NewClient(host, token string, opts ...Option) (*Client, error) {
+	baseURL, err := databaseURL(host)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{baseURL: baseURL, token: token, version: 3, http: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.version != 2 && c.version != 3 {
+		return nil, fmt.Errorf("hrana: unsupported protocol version %d", c.version)
+	}
+	return c, nil
+}
+
+func databaseURL(host string) (string, error) {
+	if host == "" {
+		return "", fmt.Errorf("hrana: database host is required")
+	}
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return "", fmt.Errorf("hrana: invalid database URL %q: %w", host, err)
+	}
+	switch u.Scheme {
+	case "libsql", "wss":
+		u.Scheme = "https"
+	case "ws":
+		u.Scheme = "http"
+	case "https", "http":
+	default:
+		return "", fmt.Errorf("hrana: unsupported URL scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("hrana: invalid database URL %q", host)
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// Statement is a SQL statement and its arguments. Args holds positional
+// arguments and sql.NamedArg values for named parameters; the name may
+// omit its ":", "@" or "$" prefix, in which case ":" is used.
+type Statement struct {
+	SQL  string
+	Args []interface{}
+}
+
+type stmt struct {
+	SQL       string     `json:"sql"`
+	Args      []value    `json:"args,omitempty"`
+	NamedArgs []namedArg `json:"named_args,omitempty"`
+	WantRows  bool       `json:"want_rows"`
+}
+
+type namedArg struct {
+	Name  string `json:"name"`
+	Value value  `json:"value"`
+}
+
+func (s Statement) encode() (stmt, error) {
+	encoded := stmt{SQL: s.SQL, WantRows: true}
+	for _, arg := range s.Args {
+		if named, ok := arg.(sql.NamedArg); ok {
+			v, err := encodeValue(named.Value)
+			if err != nil {
+				return stmt{}, err
+			}
+			name := named.Name
+			if name == "" || !strings.ContainsAny(name[:1], ":@$") {
+				name = ":" + name
+			}
+			encoded.NamedArgs = append(encoded.NamedArgs, namedArg{Name: name, Value: v})
+			continue
+		}
+		v, err := encodeValue(arg)
+		if err != nil {
+			return stmt{}, err
+		}
+		encoded.Args = append(encoded.Args, v)
+	}
+	return encoded, nil
+}
+
+// Execute runs one statement.
+func (c *Client) Execute(ctx context.Context, sql string, args ...interface{}) (*Result, error) {
+	encoded, err := Statement{SQL: sql, Args: args}.encode()
+	if err != nil {
+		return nil, err
+	}
+	results, err := c.pipeline(ctx, &stream{}, []request{{Type: "execute", Stmt: &encoded}, {Type: "close"}})
+	if err != nil {
+		return nil, err
+	}
+	return results[0].execute()
+}
+
+// Query runs one statement. It is Execute under the name shared with Tx, so
+// that both implement Querier.
+func (c *Client) Query(ctx context.Context, sql string, args ...interface{}) (*Result, error) {
+	return c.Execute(ctx, sql, args...)
+}
+
+// Batch runs statements in order, each one only if the previous succeeded,
+// in a single round trip. A failed step is reported as a *BatchError; the
+// steps after it do not run.
+func (c *Client) Batch(ctx context.Context, statements ...Statement) ([]*Result, error) {
+	b, err := newBatch(statements, nil)
+	if err != nil {
+		return nil, err
+	}
+	results, err := c.pipeline(ctx, &stream{}, []request{{Type: "batch", Batch: b}, {Type: "close"}})
+	if err != nil {
+		return nil, err
+	}
+	return results[0].batch(len(statements))
+}
+
+type request struct {
+	Type  string `json:"type"`
+	Stmt  *stmt  `json:"stmt,omitempty"`
+	Batch *batch `json:"batch,omitempty"`
+}
+
+type pipelineRequest struct {
+	Baton    *string   `json:"baton"`
+	Requests []request `json:"requests"`
+}
+
+type pipelineResponse struct {
+	Baton   *string          `json:"baton"`
+	BaseURL *string          `json:"base_url"`
+	Results []pipelineResult `json:"results"`
+}
+
+// stream is the state of a Hrana stream between pipeline requests: the
+// baton identifying it and the URL it must be continued on, which the
+// server may redirect to the primary.
+type stream struct {
+	baseURL string
+	baton   string
+}
+
+// pipeline sends requests on the stream and returns one result per request.
+// It updates the stream with the baton and base URL continuing it; an
+// empty baton means the server closed the stream.
+func (c *Client) pipeline(ctx context.Context, s *stream, requests []request) ([]pipelineResult, error) {
+	body := pipelineRequest{Requests: requests}
+	if s.baton != "" {
+		body.Baton = &s.baton
+	}
+	baseURL := s.baseURL
+	if baseURL == "" {
+		baseURL = c.baseURL
+	}
+	resp, err := c.post(ctx, baseURL+fmt.Sprintf("/v%d/pipeline", c.version), body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var decoded pipelineResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("hrana: decoding pipeline response: %w", err)
+	}
+	if len(decoded.Results) != len(requests) {
+		return nil, fmt.Errorf("hrana: expected %d results, got %d", len(requests), len(decoded.Results))
+	}
+	s.baton = ""
+	if decoded.Baton != nil {
+		s.baton = *decoded.Baton
+	}
+	if decoded.BaseURL != nil && *decoded.BaseURL != "" {
+		s.baseURL = strings.TrimSuffix(*decoded.BaseURL, "/")
+	}
+	return decoded.Results, nil
+}
+
+// closeTimeout bounds the request closing a stream that was abandoned.
+const closeTimeout = 5 * time.Second
+
+// closeStream closes st on a best-effort basis: the baton may already be
+// stale, and the server eventually expires streams anyway.
+func (c *Client) closeStream(st stream) {
+	if st.baton == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	c.pipeline(ctx, &st, []request{{Type: "close"}})
+}
+
+func (c *Client) post(ctx context.Context, endpoint string, body interface{}) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newHTTPError(resp)
+	}
+	return resp, nil
+}
+
+func newHTTPError(resp *http.Response) *Error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	e := &Error{StatusCode: resp.StatusCode}
+	var body struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+		Code    string `json:"code"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		e.Message, e.Code = body.Message, body.Code
+		if e.Message == "" {
+			e.Message = body.Error
+		}
+	} else {
+		e.Message = strings.TrimSpace(string(data))
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
@@ -0,0 +1,137 @@
+package hrana
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mr-destructive/turso-go/internal/fakehrana"
+)
+
+func TestDatabaseURL(t *testing.T) {
+	tests := map[string]string{
+		"my-db-org.turso.io":          "https://my-db-org.turso.io",
+		"libsql://my-db-org.turso.io": "https://my-db-org.turso.io",
+		"wss://my-db-org.turso.io/":   "https://my-db-org.turso.io",
+		"http://127.0.0.1:8080?tls=0": "http://127.0.0.1:8080",
+	}
+	for host, want := range tests {
+		got, err := databaseURL(host)
+		if err != nil || got != want {
+			t.Errorf("databaseURL(%q) = %q, %v, want %q", host, got, err, want)
+		}
+	}
+	for _, invalid := range []string{"", "ftp://host", "https://"} {
+		if _, err := databaseURL(invalid); err == nil {
+			t.Errorf("databaseURL(%q) should fail", invalid)
+		}
+	}
+	if _, err := NewClient("host", "token", WithProtocolVersion(1)); err == nil {
+		t.Error("expected an error for protocol version 1")
+	}
+}
+
+func TestExecute(t *testing.T) {
+	for _, version := range []int{2, 3} {
+		server, client := newTestClient(t, WithProtocolVersion(version))
+		server.Handle("SELECT id, name, score, avatar, deleted FROM users WHERE id = ? AND name = :name", func(stmt fakehrana.Stmt) (*fakehrana.Result, error) {
+			if !reflect.DeepEqual(stmt.Args, []interface{}{int64(1)}) || stmt.NamedArgs[":name"] != "ada" {
+				t.Errorf("unexpected arguments %+v", stmt)
+			}
+			return &fakehrana.Result{
+				Columns: []string{"id", "name", "score", "avatar", "deleted"},
+				Rows:    [][]interface{}{{int64(1), "ada", 9.5, []byte{0, 1}, nil}},
+			}, nil
+		})
+		result, err := client.Execute(context.Background(), "SELECT id, name, score, avatar, deleted FROM users WHERE id = ? AND name = :name", 1, sql.Named("name", "ada"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := [][]interface{}{{int64(1), "ada", 9.5, []byte{0, 1}, nil}}
+		if !reflect.DeepEqual(result.Rows, want) || len(result.Columns) != 5 || result.Columns[1].Name != "name" {
+			t.Errorf("v%d: unexpected result %+v", version, result)
+		}
+		if server.OpenStreams() != 0 {
+			t.Errorf("v%d: Execute should close its stream", version)
+		}
+	}
+}
+
+func TestExecuteWrite(t *testing.T) {
+	server, client := newTestClient(t)
+	server.Handle("INSERT INTO users (name) VALUES (?)", fakehrana.Returns(&fakehrana.Result{AffectedRowCount: 1, LastInsertRowID: 42}))
+	result, err := client.Execute(context.Background(), "INSERT INTO users (name) VALUES (?)", "ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.AffectedRowCount != 1 || result.LastInsertRowID != 42 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	server, client := newTestClient(t)
+	server.Handle("INSERT INTO users (id) VALUES (1)", func(fakehrana.Stmt) (*fakehrana.Result, error) {
+		return nil, &fakehrana.Error{Message: "UNIQUE constraint failed: users.id", Code: "SQLITE_CONSTRAINT"}
+	})
+	_, err := client.Execute(context.Background(), "INSERT INTO users (id) VALUES (1)")
+	var hranaErr *Error
+	if !errors.As(err, &hranaErr) || hranaErr.Code != "SQLITE_CONSTRAINT" {
+		t.Errorf("expected a SQLITE_CONSTRAINT error, got %v", err)
+	}
+	if _, err := client.Execute(context.Background(), "SELECT ?", struct{}{}); err == nil {
+		t.Error("expected an error for an unsupported argument")
+	}
+
+	for _, row := range [][]interface{}{{}, {"ada", "extra"}} {
+		server.Handle("SELECT name FROM broken", fakehrana.Returns(&fakehrana.Result{Columns: []string{"name"}, Rows: [][]interface{}{row}}))
+		want := fmt.Sprintf("%d values for 1 columns", len(row))
+		if _, err := client.Execute(context.Background(), "SELECT name FROM broken"); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected an error for a row not matching the columns, got %v", err)
+		}
+	}
+
+	unauthorized, err := NewClient(server.URL, "wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = unauthorized.Execute(context.Background(), "SELECT 1")
+	if !errors.As(err, &hranaErr) || hranaErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected a 401 error, got %v", err)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	server, client := newTestClient(t)
+	server.Handle("INSERT INTO users (name) VALUES (?)", fakehrana.Returns(&fakehrana.Result{AffectedRowCount: 1}))
+	server.Handle("SELECT count(*) FROM users", fakehrana.Returns(&fakehrana.Result{Columns: []string{"count(*)"}, Rows: [][]interface{}{{2}}}))
+	results, err := client.Batch(context.Background(),
+		Statement{SQL: "INSERT INTO users (name) VALUES (?)", Args: []interface{}{"ada"}},
+		Statement{SQL: "INSERT INTO users (name) VALUES (?)", Args: []interface{}{"grace"}},
+		Statement{SQL: "SELECT count(*) FROM users"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[2].Rows[0][0] != int64(2) {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	results, err = client.Batch(context.Background(),
+		Statement{SQL: "INSERT INTO users (name) VALUES (?)", Args: []interface{}{"ada"}},
+		Statement{SQL: "INSERT INTO missing VALUES (1)"},
+		Statement{SQL: "SELECT count(*) FROM users"},
+	)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Step != 1 || len(results) != 1 {
+		t.Errorf("expected step 1 to fail, got %v with %d results", err, len(results))
+	}
+	if executed := server.Executed(); executed[len(executed)-1].SQL != "INSERT INTO missing VALUES (1)" {
+		t.Errorf("the steps after a failure should not run, last executed %q", executed[len(executed)-1].SQL)
+	}
+}
@@ -0,0 +1,207 @@
+package hrana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Rows is a cursor over the rows of a statement, streamed from the Hrana v3
+// cursor endpoint. Rows are decoded one at a time as Next is called, so
+// that only what the connection buffers is read ahead of the caller and a
+// slow reader slows the server down. A Rows is not safe for concurrent use
+// and must be closed, which Next does when the rows run out.
+type Rows struct {
+	client *Client
+	ctx    context.Context
+	body   io.ReadCloser
+	dec    *json.Decoder
+	stream stream
+
+	columns []Column
+	row     []interface{}
+	err     error
+	closed  bool
+}
+
+type cursorRequest struct {
+	Baton *string `json:"baton"`
+	Batch *batch  `json:"batch"`
+}
+
+type cursorHeader struct {
+	Baton   *string `json:"baton"`
+	BaseURL *string `json:"base_url"`
+}
+
+type cursorEntry struct {
+	Type  string   `json:"type"`
+	Cols  []Column `json:"cols"`
+	Row   []value  `json:"row"`
+	Error *Error   `json:"error"`
+}
+
+// Cursor runs a statement and returns a cursor over its rows, which
+// requires protocol version 3. Errors of the statement are returned by
+// Cursor when the server reports them before the first row, and by Err
+// otherwise. Cancelling ctx stops the cursor.
+func (c *Client) Cursor(ctx context.Context, sql string, args ...interface{}) (*Rows, error) {
+	if c.version < 3 {
+		return nil, fmt.Errorf("hrana: cursors require protocol version 3")
+	}
+	b, err := newBatch([]Statement{{SQL: sql, Args: args}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.post(ctx, c.baseURL+"/v3/cursor", cursorRequest{Batch: b})
+	if err != nil {
+		return nil, err
+	}
+	rows := &Rows{client: c, ctx: ctx, body: resp.Body, dec: json.NewDecoder(resp.Body)}
+	var header cursorHeader
+	if err := rows.decode(&header); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	if header.Baton != nil {
+		rows.stream.baton = *header.Baton
+	}
+	if header.BaseURL != nil && *header.BaseURL != "" {
+		rows.stream.baseURL = strings.TrimSuffix(*header.BaseURL, "/")
+	}
+	var entry cursorEntry
+	if err := rows.decode(&entry); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	if entry.Type != "step_begin" {
+		rows.Close()
+		return nil, entryError(entry)
+	}
+	rows.columns = entry.Cols
+	return rows, nil
+}
+
+// Columns returns the columns of the rows.
+func (r *Rows) Columns() []Column {
+	return r.columns
+}
+
+// Next advances to the next row, reporting whether there is one. When it
+// returns false, the rows are closed and Err reports why they ended.
+func (r *Rows) Next() bool {
+	if r.closed {
+		return false
+	}
+	var entry cursorEntry
+	if err := r.decode(&entry); err != nil {
+		r.err = err
+		r.Close()
+		return false
+	}
+	switch entry.Type {
+	case "row":
+		if len(entry.Row) != len(r.columns) {
+			r.err = fmt.Errorf("hrana: cursor row has %d values for %d columns", len(entry.Row), len(r.columns))
+			r.Close()
+			return false
+		}
+		row := make([]interface{}, len(entry.Row))
+		for i, v := range entry.Row {
+			var err error
+			if row[i], err = v.decode(); err != nil {
+				r.err = err
+				r.Close()
+				return false
+			}
+		}
+		r.row = row
+		return true
+	case "step_end":
+	default:
+		r.err = entryError(entry)
+	}
+	r.Close()
+	return false
+}
+
+// Values returns the values of the current row: int64, float64, string,
+// []byte or nil.
+func (r *Rows) Values() []interface{} {
+	return r.row
+}
+
+// Scan copies the values of the current row into the values pointed at by
+// dest, one per column, converting them as ScanRows does for fields.
+func (r *Rows) Scan(dest ...interface{}) error {
+	return r.ScanWith(ScanOptions{}, dest...)
+}
+
+// ScanWith is Scan with options.
+func (r *Rows) ScanWith(opts ScanOptions, dest ...interface{}) error {
+	if r.row == nil {
+		return fmt.Errorf("hrana: Scan called without a row")
+	}
+	if len(dest) != len(r.columns) || len(r.row) != len(r.columns) {
+		return fmt.Errorf("hrana: expected %d destinations, got %d", len(r.columns), len(dest))
+	}
+	for i, d := range dest {
+		ptr := reflect.ValueOf(d)
+		if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+			return fmt.Errorf("hrana: destination %d is not a non-nil pointer", i)
+		}
+		if err := assign(ptr.Elem(), r.row[i], opts); err != nil {
+			return fmt.Errorf("hrana: column %q: %w", r.columns[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// Err returns the error that ended the rows, if any.
+func (r *Rows) Err() error {
+	return r.err
+}
+
+// Close stops reading the rows and closes the stream they were read on,
+// in the background so that a cancelled cursor returns at once.
+func (r *Rows) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	r.row = nil
+	r.body.Close()
+	go r.client.closeStream(r.stream)
+	return nil
+}
+
+// decode reads the next line of the response into v.
+func (r *Rows) decode(v interface{}) error {
+	err := r.dec.Decode(v)
+	switch {
+	case err == nil:
+		return nil
+	case r.ctx.Err() != nil:
+		return r.ctx.Err()
+	case errors.Is(err, io.EOF):
+		return fmt.Errorf("hrana: cursor response ended unexpectedly")
+	}
+	return fmt.Errorf("hrana: decoding cursor response: %w", err)
+}
+
+// entryError returns the error reported by a cursor entry that was not
+// expected at this point.
+func entryError(entry cursorEntry) error {
+	switch entry.Type {
+	case "step_error", "error":
+		if entry.Error == nil {
+			return &Error{Message: "unknown error"}
+		}
+		return entry.Error
+	}
+	return fmt.Errorf("hrana: unexpected cursor entry %q", entry.Type)
+}
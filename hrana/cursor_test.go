@@ -0,0 +1,170 @@
+package hrana
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mr-destructive/turso-go/internal/fakehrana"
+)
+
+func TestCursor(t *testing.T) {
+	server, client := newTestClient(t)
+	server.Handle("SELECT id, name, bio FROM users WHERE id > ?", fakehrana.Returns(&fakehrana.Result{
+		Columns: []string{"id", "name", "bio"},
+		Rows:    [][]interface{}{{1, "ada", "mathematician"}, {2, "grace", nil}},
+	}))
+	rows, err := client.Cursor(context.Background(), "SELECT id, name, bio FROM users WHERE id > ?", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	if got := rows.Columns(); len(got) != 3 || got[1].Name != "name" {
+		t.Errorf("unexpected columns %+v", got)
+	}
+
+	type user struct {
+		id   int
+		name string
+		bio  sql.NullString
+	}
+	var users []user
+	for rows.Next() {
+		var u user
+		if err := rows.Scan(&u.id, &u.name, &u.bio); err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []user{{1, "ada", sql.NullString{String: "mathematician", Valid: true}}, {2, "grace", sql.NullString{}}}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("got %+v, want %+v", users, want)
+	}
+	if args := server.Executed()[0].Args; !reflect.DeepEqual(args, []interface{}{int64(0)}) {
+		t.Errorf("unexpected args %v", args)
+	}
+	waitForStreams(t, server)
+}
+
+func TestCursorErrors(t *testing.T) {
+	server, client := newTestClient(t)
+	_, err := client.Cursor(context.Background(), "SELECT * FROM missing")
+	var herr *Error
+	if !errors.As(err, &herr) || herr.Code != "SQLITE_ERROR" {
+		t.Errorf("expected a statement error, got %v", err)
+	}
+	waitForStreams(t, server)
+
+	server.Handle("SELECT name FROM users", fakehrana.Returns(&fakehrana.Result{Columns: []string{"name"}, Rows: [][]interface{}{{"ada"}}}))
+	rows, err := client.Cursor(context.Background(), "SELECT name FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	rows.Next()
+	var n int
+	if err := rows.Scan(&n); err == nil {
+		t.Error("expected a conversion error")
+	}
+	if err := rows.Scan(n); err == nil {
+		t.Error("expected an error scanning into a non-pointer")
+	}
+	if err := rows.Scan(&n, &n); err == nil {
+		t.Error("expected an error for too many destinations")
+	}
+
+	server.Handle("SELECT name FROM broken", fakehrana.Returns(&fakehrana.Result{Columns: []string{"name"}, Rows: [][]interface{}{{"ada", "extra"}}}))
+	rows, err = client.Cursor(context.Background(), "SELECT name FROM broken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows.Next() || rows.Err() == nil || !strings.Contains(rows.Err().Error(), "2 values for 1 columns") {
+		t.Errorf("expected an error for a row not matching the columns, got %v", rows.Err())
+	}
+
+	v2, err := NewClient(server.URL, server.Token, WithProtocolVersion(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v2.Cursor(context.Background(), "SELECT name FROM users"); err == nil {
+		t.Error("expected cursors to require version 3")
+	}
+}
+
+func TestCursorCanceled(t *testing.T) {
+	server, client := newTestClient(t)
+	large := &fakehrana.Result{Columns: []string{"id", "payload"}}
+	for i := 0; i < 50000; i++ {
+		large.Rows = append(large.Rows, []interface{}{i, "0123456789abcdef0123456789abcdef"})
+	}
+	server.Handle("SELECT id, payload FROM events", fakehrana.Returns(large))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rows, err := client.Cursor(ctx, "SELECT id, payload FROM events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	read := 0
+	for rows.Next() {
+		read++
+		if read == 100 {
+			cancel()
+		}
+	}
+	if !errors.Is(rows.Err(), context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", rows.Err())
+	}
+	if read >= len(large.Rows) {
+		t.Errorf("read all %d rows despite the cancellation", read)
+	}
+	waitForStreams(t, server)
+}
+
+// stalledTransport holds pipeline requests, which close streams, until
+// release is closed.
+type stalledTransport struct {
+	release chan struct{}
+}
+
+func (s stalledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/pipeline") {
+		select {
+		case <-s.release:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCursorCloseDoesNotWait(t *testing.T) {
+	transport := stalledTransport{release: make(chan struct{})}
+	server, client := newTestClient(t, WithHTTPClient(&http.Client{Transport: transport}))
+	server.Handle("SELECT name FROM users", fakehrana.Returns(&fakehrana.Result{Columns: []string{"name"}, Rows: [][]interface{}{{"ada"}}}))
+	rows, err := client.Cursor(context.Background(), "SELECT name FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := make(chan struct{})
+	go func() {
+		rows.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close waited for the stream to be closed")
+	}
+	close(transport.release)
+	waitForStreams(t, server)
+}
@@ -0,0 +1,56 @@
+package hrana
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mr-destructive/turso-go/internal/fakehrana"
+)
+
+// The fixtures shared by the tests of this package build on
+// internal/fakehrana, the fake behind turstest.DatabaseServer, since
+// turstest imports this package.
+
+// newTestClient starts a fake database, closed when the test ends, and
+// returns a client for it.
+func newTestClient(t *testing.T, opts ...Option) (*fakehrana.Server, *Client) {
+	t.Helper()
+	server := fakehrana.NewServer()
+	t.Cleanup(server.Close)
+	client, err := NewClient(server.URL, server.Token, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, client
+}
+
+func executedSQL(server *fakehrana.Server) []string {
+	var sql []string
+	for _, stmt := range server.Executed() {
+		sql = append(sql, stmt.SQL)
+	}
+	return sql
+}
+
+func waitForStreams(t *testing.T, server *fakehrana.Server) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for server.OpenStreams() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the stream to close")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+package hrana
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Error is an error reported by the database: a failed statement, or an
+// HTTP error when StatusCode is set.
+type Error struct {
+	Message string `json:"message"`
+	// Code is the SQLite or server error code, such as "SQLITE_CONSTRAINT".
+	Code       string `json:"code"`
+	StatusCode int    `json:"-"`
+}
+
+func (e *Error) Error() string {
+	msg := "hrana: "
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf("%d %s: ", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	msg += e.Message
+	if e.Code != "" {
+		msg += " (" + e.Code + ")"
+	}
+	return msg
+}
+
+// BatchError reports the step of a batch that failed.
+type BatchError struct {
+	Step int
+	Err  *Error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch step %d: %v", e.Step, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// Column describes a result column. DeclType is the declared type of the
+// column, empty for expressions.
+type Column struct {
+	Name     string `json:"name"`
+	DeclType string `json:"decltype"`
+}
+
+// Result is the result of a statement. Row values are int64, float64,
+// string, []byte or nil.
+type Result struct {
+	Columns          []Column
+	Rows             [][]interface{}
+	AffectedRowCount int64
+	// LastInsertRowID is zero when the statement inserted no row.
+	LastInsertRowID int64
+	RowsRead        int64
+	RowsWritten     int64
+}
+
+type stmtResult struct {
+	Cols             []Column  `json:"cols"`
+	Rows             [][]value `json:"rows"`
+	AffectedRowCount int64     `json:"affected_row_count"`
+	LastInsertRowID  *string   `json:"last_insert_rowid"`
+	RowsRead         int64     `json:"rows_read"`
+	RowsWritten      int64     `json:"rows_written"`
+}
+
+func (r *stmtResult) decode() (*Result, error) {
+	result := &Result{
+		Columns:          r.Cols,
+		Rows:             make([][]interface{}, len(r.Rows)),
+		AffectedRowCount: r.AffectedRowCount,
+		RowsRead:         r.RowsRead,
+		RowsWritten:      r.RowsWritten,
+	}
+	if r.LastInsertRowID != nil {
+		id, err := strconv.ParseInt(*r.LastInsertRowID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("hrana: invalid last_insert_rowid %q", *r.LastInsertRowID)
+		}
+		result.LastInsertRowID = id
+	}
+	for i, row := range r.Rows {
+		if len(row) != len(r.Cols) {
+			return nil, fmt.Errorf("hrana: row has %d values for %d columns", len(row), len(r.Cols))
+		}
+		decoded := make([]interface{}, len(row))
+		for j, v := range row {
+			var err error
+			if decoded[j], err = v.decode(); err != nil {
+				return nil, err
+			}
+		}
+		result.Rows[i] = decoded
+	}
+	return result, nil
+}
+
+type batch struct {
+	Steps []batchStep `json:"steps"`
+}
+
+type batchStep struct {
+	Condition *condition `json:"condition,omitempty"`
+	Stmt      stmt       `json:"stmt"`
+}
+
+type condition struct {
+	Type string `json:"type"`
+	Step *int   `json:"step,omitempty"`
+}
+
+func okCondition(step int) *condition {
+	return &condition{Type: "ok", Step: &step}
+}
+
+// newBatch chains the statements so that each runs only if the previous
+// one succeeded. first, when set, guards the first statement.
+func newBatch(statements []Statement, first *condition) (*batch, error) {
+	b := &batch{Steps: make([]batchStep, len(statements))}
+	for i, s := range statements {
+		encoded, err := s.encode()
+		if err != nil {
+			return nil, fmt.Errorf("batch step %d: %w", i, err)
+		}
+		b.Steps[i].Stmt = encoded
+		if i > 0 {
+			b.Steps[i].Condition = okCondition(i - 1)
+		} else {
+			b.Steps[i].Condition = first
+		}
+	}
+	return b, nil
+}
+
+type batchResult struct {
+	StepResults []*stmtResult `json:"step_results"`
+	StepErrors  []*Error      `json:"step_errors"`
+}
+
+type pipelineResult struct {
+	Type     string `json:"type"`
+	Response *struct {
+		Type   string          `json:"type"`
+		Result json.RawMessage `json:"result"`
+	} `json:"response"`
+	Error *Error `json:"error"`
+}
+
+func (r pipelineResult) result(kind string, v interface{}) error {
+	switch r.Type {
+	case "ok":
+		if r.Response == nil || r.Response.Type != kind {
+			return fmt.Errorf("hrana: expected a %s response", kind)
+		}
+		if v == nil || len(r.Response.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(r.Response.Result, v)
+	case "error":
+		if r.Error == nil {
+			return &Error{Message: "unknown error"}
+		}
+		return r.Error
+	}
+	return fmt.Errorf("hrana: unknown result type %q", r.Type)
+}
+
+func (r pipelineResult) execute() (*Result, error) {
+	var result stmtResult
+	if err := r.result("execute", &result); err != nil {
+		return nil, err
+	}
+	return result.decode()
+}
+
+// batch returns the results of the n steps of a batch, stopping at the first
+// failed step.
+func (r pipelineResult) batch(n int) ([]*Result, error) {
+	var br batchResult
+	if err := r.result("batch", &br); err != nil {
+		return nil, err
+	}
+	results := make([]*Result, 0, n)
+	for i := 0; i < n; i++ {
+		if i < len(br.StepErrors) && br.StepErrors[i] != nil {
+			return results, &BatchError{Step: i, Err: br.StepErrors[i]}
+		}
+		if i >= len(br.StepResults) || br.StepResults[i] == nil {
+			return results, fmt.Errorf("hrana: batch step %d did not run", i)
+		}
+		result, err := br.StepResults[i].decode()
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
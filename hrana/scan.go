@@ -0,0 +1,313 @@
+package hrana
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Querier runs a statement and returns its rows. It is implemented by
+// Client and Tx.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (*Result, error)
+}
+
+var (
+	_ Querier = (*Client)(nil)
+	_ Querier = (*Tx)(nil)
+)
+
+// ScanOptions configures how ScanRowsWith, QueryWith and Rows.ScanWith
+// convert column values.
+type ScanOptions struct {
+	// ParseTimes parses text in one of the SQLite date formats into
+	// time.Time and sql.NullTime fields. Without it, assigning a value to
+	// such a field is an error.
+	ParseTimes bool
+}
+
+// Query runs a statement and maps its rows to values of the struct type T
+// with ScanRows.
+func Query[T any](ctx context.Context, q Querier, sql string, args ...interface{}) ([]T, error) {
+	return QueryWith[T](ctx, q, ScanOptions{}, sql, args...)
+}
+
+// QueryWith is Query with options.
+func QueryWith[T any](ctx context.Context, q Querier, opts ScanOptions, sql string, args ...interface{}) ([]T, error) {
+	result, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return ScanRowsWith[T](result, opts)
+}
+
+// ScanRows maps the rows of result to values of the struct type T. Each
+// column is assigned to the field whose `db` tag names it or, for fields
+// without a tag, whose name matches it regardless of case. Fields tagged
+// `db:"-"` are ignored and the fields of embedded structs are promoted.
+//
+// NULL is only assigned to pointer and slice fields, which are set to nil,
+// and to sql.Scanner fields such as sql.NullString. Blobs are assigned to
+// []byte fields. Dates are only assigned with ScanRowsWith and
+// ScanOptions.ParseTimes. A column without a matching field, two columns
+// matching the same field, or a value that does not fit its field, is an
+// error naming them.
+func ScanRows[T any](result *Result) ([]T, error) {
+	return ScanRowsWith[T](result, ScanOptions{})
+}
+
+// ScanRowsWith is ScanRows with options.
+func ScanRowsWith[T any](result *Result, opts ScanOptions) ([]T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	fields, err := structFields(typ)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([][]int, len(result.Columns))
+	seen := make(map[string]string, len(result.Columns))
+	for i, col := range result.Columns {
+		name := strings.ToLower(col.Name)
+		index, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("hrana: column %q has no matching field in %s", col.Name, typ)
+		}
+		if previous, ok := seen[name]; ok {
+			return nil, fmt.Errorf("hrana: columns %q and %q both match field %s.%s; alias one of them",
+				previous, col.Name, typ, typ.FieldByIndex(index).Name)
+		}
+		seen[name] = col.Name
+		indexes[i] = index
+	}
+	rows := make([]T, len(result.Rows))
+	for i, row := range result.Rows {
+		dst := reflect.ValueOf(&rows[i]).Elem()
+		for j, v := range row {
+			field := dst.FieldByIndex(indexes[j])
+			if err := assign(field, v, opts); err != nil {
+				return nil, fmt.Errorf("hrana: row %d: column %q into field %s.%s: %w",
+					i, result.Columns[j].Name, typ, typ.FieldByIndex(indexes[j]).Name, err)
+			}
+		}
+	}
+	return rows, nil
+}
+
+// fieldCache maps struct types to their lowercased column names and field
+// indexes.
+var fieldCache sync.Map
+
+func structFields(typ reflect.Type) (map[string][]int, error) {
+	if cached, ok := fieldCache.Load(typ); ok {
+		return cached.(map[string][]int), nil
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("hrana: cannot scan rows into %s: not a struct", typ)
+	}
+	fields := map[string][]int{}
+	if err := collectFields(typ, nil, fields); err != nil {
+		return nil, err
+	}
+	fieldCache.Store(typ, fields)
+	return fields, nil
+}
+
+func collectFields(typ reflect.Type, parent []int, fields map[string][]int) error {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		index := append(append([]int(nil), parent...), i)
+		tag, hasTag := f.Tag.Lookup("db")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && !hasTag && f.Type.Kind() == reflect.Struct && !isScalarStruct(f.Type) {
+			if err := collectFields(f.Type, index, fields); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name := tag
+		if name == "" {
+			name = f.Name
+		}
+		name = strings.ToLower(name)
+		if _, ok := fields[name]; ok {
+			return fmt.Errorf("hrana: %s has several fields for column %q", typ, name)
+		}
+		fields[name] = index
+	}
+	return nil
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	nullTimeType = reflect.TypeOf(sql.NullTime{})
+	scannerType  = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// isScalarStruct reports whether values of the struct type typ are
+// assigned whole rather than having their fields promoted.
+func isScalarStruct(typ reflect.Type) bool {
+	return typ == timeType || reflect.PointerTo(typ).Implements(scannerType)
+}
+
+// assign stores the column value v, as decoded from a row, in dst.
+func assign(dst reflect.Value, v interface{}, opts ScanOptions) error {
+	switch dst.Type() {
+	case timeType:
+		if v == nil {
+			return fmt.Errorf("cannot assign NULL to %s; use *time.Time or sql.NullTime", dst.Type())
+		}
+		t, err := parseTime(v, opts)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	case nullTimeType:
+		if v == nil {
+			dst.Set(reflect.ValueOf(sql.NullTime{}))
+			return nil
+		}
+		t, err := parseTime(v, opts)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(sql.NullTime{Time: t, Valid: true}))
+		return nil
+	}
+	if scanner, ok := dst.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(v)
+	}
+	if dst.Kind() == reflect.Slice && v == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if dst.Kind() == reflect.Pointer {
+		if v == nil {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		elem := reflect.New(dst.Type().Elem())
+		if err := assign(elem.Elem(), v, opts); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+	if dst.Kind() == reflect.Interface && dst.NumMethod() == 0 {
+		if v == nil {
+			dst.Set(reflect.Zero(dst.Type()))
+		} else {
+			dst.Set(reflect.ValueOf(v))
+		}
+		return nil
+	}
+	switch v := v.(type) {
+	case nil:
+		return fmt.Errorf("cannot assign NULL to %s; use a pointer or sql.Null type", dst.Type())
+	case int64:
+		return assignInt(dst, v)
+	case float64:
+		switch dst.Kind() {
+		case reflect.Float32, reflect.Float64:
+			dst.SetFloat(v)
+			return nil
+		}
+	case string:
+		switch {
+		case dst.Kind() == reflect.String:
+			dst.SetString(v)
+			return nil
+		case dst.Type() == bytesType:
+			dst.SetBytes([]byte(v))
+			return nil
+		}
+	case []byte:
+		switch {
+		case dst.Type() == bytesType:
+			dst.SetBytes(append([]byte(nil), v...))
+			return nil
+		case dst.Kind() == reflect.String:
+			dst.SetString(string(v))
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot assign %s to %s", valueKind(v), dst.Type())
+}
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+func assignInt(dst reflect.Value, v int64) error {
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if dst.OverflowInt(v) {
+			return fmt.Errorf("integer %d overflows %s", v, dst.Type())
+		}
+		dst.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v < 0 || dst.OverflowUint(uint64(v)) {
+			return fmt.Errorf("integer %d overflows %s", v, dst.Type())
+		}
+		dst.SetUint(uint64(v))
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(float64(v))
+	case reflect.Bool:
+		dst.SetBool(v != 0)
+	default:
+		return fmt.Errorf("cannot assign integer to %s", dst.Type())
+	}
+	return nil
+}
+
+// valueKind names the SQLite type of a decoded value.
+func valueKind(v interface{}) string {
+	switch v.(type) {
+	case int64:
+		return "integer"
+	case float64:
+		return "float"
+	case string:
+		return "text"
+	case []byte:
+		return "blob"
+	}
+	return "NULL"
+}
+
+// timeFormats are the date and time formats SQLite date functions produce
+// and accept, without and with a time zone.
+var timeFormats = []string{
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// parseTime parses a text value in one of timeFormats when
+// opts.ParseTimes is set. Times without a zone are UTC, as in SQLite.
+func parseTime(v interface{}, opts ScanOptions) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("cannot assign %s to time.Time; dates must be text", valueKind(v))
+	}
+	if !opts.ParseTimes {
+		return time.Time{}, fmt.Errorf("cannot assign text to time.Time without ScanOptions.ParseTimes")
+	}
+	s = strings.TrimSpace(s)
+	for _, format := range timeFormats {
+		if t, err := time.ParseInLocation(format, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a date", s)
+}
@@ -0,0 +1,179 @@
+package hrana
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mr-destructive/turso-go/internal/fakehrana"
+)
+
+type audit struct {
+	CreatedAt time.Time     `db:"created_at"`
+	DeletedAt *time.Time    `db:"deleted_at"`
+	Reviewed  sql.NullTime  `db:"reviewed_at"`
+	Editor    sql.NullInt64 `db:"editor_id"`
+}
+
+type user struct {
+	ID       int64   `db:"id"`
+	Name     string  `db:"name"`
+	Nickname *string `db:"nickname"`
+	Bio      sql.NullString
+	Avatar   []byte  `db:"avatar"`
+	Score    float64 `db:"score"`
+	Admin    bool    `db:"is_admin"`
+	Cached   string  `db:"-"`
+	audit
+}
+
+func columns(names ...string) []Column {
+	cols := make([]Column, len(names))
+	for i, name := range names {
+		cols[i] = Column{Name: name}
+	}
+	return cols
+}
+
+func TestScanRows(t *testing.T) {
+	result := &Result{
+		Columns: columns("id", "name", "nickname", "BIO", "avatar", "score", "is_admin", "created_at", "deleted_at", "reviewed_at", "editor_id"),
+		Rows: [][]interface{}{
+			{int64(1), "ada", "countess", "mathematician", []byte{0xde, 0xad}, 9.5, int64(1), "2024-03-01 12:30:00", nil, "2024-03-02T08:00:00Z", int64(7)},
+			{int64(2), "grace", nil, nil, nil, int64(7), int64(0), "2024-03-05", "2024-04-01 10:00:00.250", nil, nil},
+		},
+	}
+	users, err := ScanRowsWith[user](result, ScanOptions{ParseTimes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nickname := "countess"
+	deleted := time.Date(2024, 4, 1, 10, 0, 0, 250e6, time.UTC)
+	want := []user{
+		{
+			ID: 1, Name: "ada", Nickname: &nickname, Bio: sql.NullString{String: "mathematician", Valid: true},
+			Avatar: []byte{0xde, 0xad}, Score: 9.5, Admin: true,
+			audit: audit{
+				CreatedAt: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+				Reviewed:  sql.NullTime{Time: time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC), Valid: true},
+				Editor:    sql.NullInt64{Int64: 7, Valid: true},
+			},
+		},
+		{
+			ID: 2, Name: "grace", Score: 7,
+			audit: audit{CreatedAt: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), DeletedAt: &deleted},
+		},
+	}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("scanned\n%+v\nwant\n%+v", users, want)
+	}
+}
+
+func TestScanRowsErrors(t *testing.T) {
+	parseTimes := ScanOptions{ParseTimes: true}
+	tests := map[string]struct {
+		result *Result
+		opts   ScanOptions
+		want   string
+	}{
+		"unknown column": {
+			&Result{Columns: columns("id", "email")},
+			ScanOptions{},
+			`column "email" has no matching field in hrana.user`,
+		},
+		"duplicate column": {
+			&Result{Columns: columns("id", "name", "ID")},
+			ScanOptions{},
+			`columns "id" and "ID" both match field hrana.user.ID`,
+		},
+		"type mismatch": {
+			&Result{Columns: columns("id"), Rows: [][]interface{}{{"one"}}},
+			ScanOptions{},
+			`row 0: column "id" into field hrana.user.ID: cannot assign text to int64`,
+		},
+		"null": {
+			&Result{Columns: columns("name"), Rows: [][]interface{}{{nil}}},
+			ScanOptions{},
+			`column "name" into field hrana.user.Name: cannot assign NULL to string`,
+		},
+		"date without ParseTimes": {
+			&Result{Columns: columns("created_at"), Rows: [][]interface{}{{"2024-03-05"}}},
+			ScanOptions{},
+			`cannot assign text to time.Time without ScanOptions.ParseTimes`,
+		},
+		"bad date": {
+			&Result{Columns: columns("created_at"), Rows: [][]interface{}{{"yesterday"}}},
+			parseTimes,
+			`column "created_at" into field hrana.user.CreatedAt: cannot parse "yesterday" as a date`,
+		},
+		"numeric date": {
+			&Result{Columns: columns("created_at"), Rows: [][]interface{}{{int64(1700000000)}}},
+			parseTimes,
+			`cannot assign integer to time.Time`,
+		},
+	}
+	for name, tt := range tests {
+		_, err := ScanRowsWith[user](tt.result, tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want an error containing %q", name, err, tt.want)
+		}
+	}
+
+	type small struct {
+		N int8 `db:"n"`
+	}
+	if _, err := ScanRows[small](&Result{Columns: columns("n"), Rows: [][]interface{}{{int64(300)}}}); err == nil || !strings.Contains(err.Error(), "overflows int8") {
+		t.Errorf("expected an overflow error, got %v", err)
+	}
+	_, err := ScanRows[struct{ N int8 }](&Result{Columns: columns("n"), Rows: [][]interface{}{{"x"}}})
+	if err == nil || !strings.Contains(err.Error(), "field struct { N int8 }.N") {
+		t.Errorf("expected the anonymous struct to be named, got %v", err)
+	}
+	if _, err := ScanRows[string](&Result{}); err == nil {
+		t.Error("expected an error scanning into a non-struct type")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	server, client := newTestClient(t)
+	server.Handle("SELECT id, name FROM users WHERE id > ?", fakehrana.Returns(&fakehrana.Result{
+		Columns: []string{"id", "name"},
+		Rows:    [][]interface{}{{1, "ada"}, {2, "grace"}},
+	}))
+	type row struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	ctx := context.Background()
+	rows, err := Query[row](ctx, client, "SELECT id, name FROM users WHERE id > ?", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []row{{1, "ada"}, {2, "grace"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %+v, want %+v", rows, want)
+	}
+
+	tx, err := client.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	if rows, err := Query[row](ctx, tx, "SELECT id, name FROM users WHERE id > ?", 1); err != nil || len(rows) != 2 {
+		t.Errorf("got %+v, %v in a transaction", rows, err)
+	}
+
+	server.Handle("SELECT created_at FROM users", fakehrana.Returns(&fakehrana.Result{
+		Columns: []string{"created_at"},
+		Rows:    [][]interface{}{{"2024-03-05 10:00:00"}},
+	}))
+	type stamp struct {
+		CreatedAt time.Time `db:"created_at"`
+	}
+	stamps, err := QueryWith[stamp](ctx, client, ScanOptions{ParseTimes: true}, "SELECT created_at FROM users")
+	if err != nil || len(stamps) != 1 || !stamps[0].CreatedAt.Equal(time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("got %+v, %v with ParseTimes", stamps, err)
+	}
+}
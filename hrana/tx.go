@@ -0,0 +1,153 @@
+package hrana
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrTxDone is returned by the methods of a Tx that has been committed,
+// rolled back or abandoned.
+var ErrTxDone = errors.New("hrana: transaction has already been committed or rolled back")
+
+// Tx is an interactive transaction. Its statements run on one Hrana stream,
+// identified by the baton handed back by every pipeline response, and a
+// Tx must not be used concurrently with itself.
+//
+// The transaction is rolled back when the context passed to Begin is done,
+// or when the Tx is garbage collected without Commit or Rollback.
+type Tx struct {
+	*txState
+}
+
+type txState struct {
+	client *Client
+
+	mu     sync.Mutex
+	stream stream
+	done   bool
+	// stop ends the goroutine rolling back on context cancellation.
+	stop chan struct{}
+}
+
+// Begin starts a transaction.
+func (c *Client) Begin(ctx context.Context) (*Tx, error) {
+	state := &txState{client: c, stop: make(chan struct{})}
+	begin, err := Statement{SQL: "BEGIN"}.encode()
+	if err != nil {
+		return nil, err
+	}
+	results, err := c.pipeline(ctx, &state.stream, []request{{Type: "execute", Stmt: &begin}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := results[0].execute(); err != nil {
+		state.abandon()
+		return nil, err
+	}
+	if state.stream.baton == "" {
+		return nil, errors.New("hrana: server closed the transaction stream")
+	}
+	if done := ctx.Done(); done != nil {
+		go func() {
+			select {
+			case <-done:
+				state.abandon()
+			case <-state.stop:
+			}
+		}()
+	}
+	tx := &Tx{state}
+	// The finalizer is set on the Tx rather than its state, which the
+	// goroutine above keeps reachable until the transaction ends.
+	runtime.SetFinalizer(tx, func(tx *Tx) { tx.abandon() })
+	return tx, nil
+}
+
+// Exec runs a statement that returns no rows.
+func (tx *Tx) Exec(ctx context.Context, sql string, args ...interface{}) (*Result, error) {
+	return tx.execute(ctx, Statement{SQL: sql, Args: args}, false)
+}
+
+// Query runs a statement and returns its rows.
+func (tx *Tx) Query(ctx context.Context, sql string, args ...interface{}) (*Result, error) {
+	return tx.execute(ctx, Statement{SQL: sql, Args: args}, true)
+}
+
+func (tx *Tx) execute(ctx context.Context, s Statement, wantRows bool) (*Result, error) {
+	encoded, err := s.encode()
+	if err != nil {
+		return nil, err
+	}
+	encoded.WantRows = wantRows
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return nil, ErrTxDone
+	}
+	results, err := tx.client.pipeline(ctx, &tx.stream, []request{{Type: "execute", Stmt: &encoded}})
+	if err != nil {
+		// The server may or may not have consumed the baton: give up on the
+		// stream, which rolls the transaction back.
+		tx.finish()
+		go tx.client.closeStream(tx.stream)
+		return nil, err
+	}
+	if tx.stream.baton == "" {
+		tx.finish()
+	}
+	return results[0].execute()
+}
+
+// Commit commits the transaction and closes its stream.
+func (tx *Tx) Commit(ctx context.Context) error {
+	return tx.end(ctx, "COMMIT")
+}
+
+// Rollback rolls the transaction back and closes its stream.
+func (tx *Tx) Rollback(ctx context.Context) error {
+	return tx.end(ctx, "ROLLBACK")
+}
+
+func (tx *Tx) end(ctx context.Context, sql string) error {
+	encoded, err := Statement{SQL: sql}.encode()
+	if err != nil {
+		return err
+	}
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.finish()
+	results, err := tx.client.pipeline(ctx, &tx.stream, []request{{Type: "execute", Stmt: &encoded}, {Type: "close"}})
+	if err != nil {
+		go tx.client.closeStream(tx.stream)
+		return err
+	}
+	_, err = results[0].execute()
+	return err
+}
+
+// finish marks the transaction done. The caller holds mu.
+func (s *txState) finish() {
+	if !s.done {
+		s.done = true
+		close(s.stop)
+	}
+}
+
+// abandon closes the stream of a transaction that was neither committed
+// nor rolled back, which makes the server roll it back.
+func (s *txState) abandon() {
+	s.mu.Lock()
+	if s.done {
+		s.mu.Unlock()
+		return
+	}
+	s.finish()
+	st := s.stream
+	s.mu.Unlock()
+	go s.client.closeStream(st)
+}
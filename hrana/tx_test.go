@@ -0,0 +1,119 @@
+package hrana
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/mr-destructive/turso-go/internal/fakehrana"
+)
+
+func handleAccounts(server *fakehrana.Server) {
+	server.Handle("UPDATE accounts SET balance = balance + ? WHERE id = ?", fakehrana.Returns(&fakehrana.Result{AffectedRowCount: 1}))
+	server.Handle("SELECT balance FROM accounts WHERE id = ?", fakehrana.Returns(&fakehrana.Result{Columns: []string{"balance"}, Rows: [][]interface{}{{90}}}))
+}
+
+func TestTxCommit(t *testing.T) {
+	server, client := newTestClient(t)
+	handleAccounts(server)
+	// Transactions start on a replica and are redirected to the primary.
+	server.SetPrimary("/primary")
+	ctx := context.Background()
+
+	tx, err := client.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(ctx, "UPDATE accounts SET balance = balance + ? WHERE id = ?", -10, 1); err != nil {
+		t.Fatal(err)
+	}
+	result, err := tx.Query(ctx, "SELECT balance FROM accounts WHERE id = ?", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows[0][0] != int64(90) {
+		t.Errorf("unexpected rows %v", result.Rows)
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO missing VALUES (1)"); err == nil {
+		t.Error("expected a statement error")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"BEGIN", "UPDATE accounts SET balance = balance + ? WHERE id = ?", "SELECT balance FROM accounts WHERE id = ?", "INSERT INTO missing VALUES (1)", "COMMIT"}
+	if got := executedSQL(server); !equalStrings(got, want) {
+		t.Errorf("executed %q, want %q", got, want)
+	}
+	if server.OpenStreams() != 0 {
+		t.Error("Commit should close the stream")
+	}
+	if _, err := tx.Exec(ctx, "SELECT 1"); !errors.Is(err, ErrTxDone) {
+		t.Errorf("expected ErrTxDone, got %v", err)
+	}
+	if err := tx.Rollback(ctx); !errors.Is(err, ErrTxDone) {
+		t.Errorf("expected ErrTxDone, got %v", err)
+	}
+}
+
+func TestTxRollback(t *testing.T) {
+	server, client := newTestClient(t)
+	ctx := context.Background()
+	tx, err := client.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := executedSQL(server); !equalStrings(got, []string{"BEGIN", "ROLLBACK"}) || server.OpenStreams() != 0 {
+		t.Errorf("executed %q with %d open streams", got, server.OpenStreams())
+	}
+}
+
+func TestTxContextCanceled(t *testing.T) {
+	server, client := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	tx, err := client.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	waitForStreams(t, server)
+	if _, err := tx.Exec(context.Background(), "SELECT 1"); !errors.Is(err, ErrTxDone) {
+		t.Errorf("expected ErrTxDone, got %v", err)
+	}
+}
+
+func TestTxAbandoned(t *testing.T) {
+	server, client := newTestClient(t)
+	if _, err := client.Begin(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for server.OpenStreams() != 0 && time.Now().Before(deadline) {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if server.OpenStreams() != 0 {
+		t.Error("an abandoned transaction should close its stream")
+	}
+}
+
+func TestTxRequestFailure(t *testing.T) {
+	server, client := newTestClient(t)
+	tx, err := client.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := tx.Exec(ctx, "SELECT 1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	waitForStreams(t, server)
+	if err := tx.Commit(context.Background()); !errors.Is(err, ErrTxDone) {
+		t.Errorf("expected ErrTxDone after a failed request, got %v", err)
+	}
+}
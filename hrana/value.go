@@ -0,0 +1,147 @@
+package hrana
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// value is a Hrana value on the wire. Integers are sent as strings so that
+// 64-bit values survive JSON, and blobs as base64.
+type value struct {
+	Type   string      `json:"type"`
+	Value  interface{} `json:"value,omitempty"`
+	Base64 string      `json:"base64,omitempty"`
+}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// encodeValue converts a Go value to a Hrana value. It accepts nil, the
+// integer, float, bool and string kinds, []byte, time.Time and
+// driver.Valuer.
+func encodeValue(v interface{}) (value, error) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() && rv.Type().Elem().Implements(valuerType) {
+		// Like database/sql, encode a nil pointer to a Valuer with a value
+		// receiver as null instead of calling Value on it.
+		return value{Type: "null"}, nil
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		resolved, err := valuer.Value()
+		if err != nil {
+			return value{}, err
+		}
+		v = resolved
+	}
+	switch v := v.(type) {
+	case nil:
+		return value{Type: "null"}, nil
+	case []byte:
+		if v == nil {
+			return value{Type: "null"}, nil
+		}
+		return value{Type: "blob", Base64: base64.StdEncoding.EncodeToString(v)}, nil
+	case time.Time:
+		return value{Type: "text", Value: v.Format(time.RFC3339Nano)}, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return value{Type: "null"}, nil
+		}
+		return encodeValue(rv.Elem().Interface())
+	case reflect.Bool:
+		if rv.Bool() {
+			return value{Type: "integer", Value: "1"}, nil
+		}
+		return value{Type: "integer", Value: "0"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value{Type: "integer", Value: strconv.FormatInt(rv.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return value{}, fmt.Errorf("hrana: integer %d overflows int64", rv.Uint())
+		}
+		return value{Type: "integer", Value: strconv.FormatUint(rv.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return value{}, fmt.Errorf("hrana: cannot encode float %v", f)
+		}
+		return value{Type: "float", Value: f}, nil
+	case reflect.String:
+		return value{Type: "text", Value: rv.String()}, nil
+	}
+	return value{}, fmt.Errorf("hrana: unsupported argument type %T", v)
+}
+
+// decode converts a Hrana value to int64, float64, string, []byte or nil.
+func (v value) decode() (interface{}, error) {
+	switch v.Type {
+	case "null":
+		return nil, nil
+	case "integer":
+		s, ok := v.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("hrana: integer value %v is not a string", v.Value)
+		}
+		return strconv.ParseInt(s, 10, 64)
+	case "float":
+		f, ok := v.Value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("hrana: float value %v is not a number", v.Value)
+		}
+		return f, nil
+	case "text":
+		s, ok := v.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("hrana: text value %v is not a string", v.Value)
+		}
+		return s, nil
+	case "blob":
+		return base64.StdEncoding.DecodeString(v.Base64)
+	}
+	return nil, fmt.Errorf("hrana: unknown value type %q", v.Type)
+}
+
+// UnmarshalJSON keeps integers exact and accepts blobs in padded or
+// unpadded base64.
+func (v *value) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type   string          `json:"type"`
+		Value  json.RawMessage `json:"value"`
+		Base64 string          `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	v.Type, v.Value, v.Base64 = raw.Type, nil, raw.Base64
+	if v.Type == "blob" {
+		if padding := len(raw.Base64) % 4; padding != 0 {
+			v.Base64 += "===="[padding:]
+		}
+		return nil
+	}
+	if len(raw.Value) == 0 {
+		return nil
+	}
+	switch v.Type {
+	case "float":
+		var f float64
+		if err := json.Unmarshal(raw.Value, &f); err != nil {
+			return err
+		}
+		v.Value = f
+	default:
+		var s string
+		if err := json.Unmarshal(raw.Value, &s); err != nil {
+			return fmt.Errorf("hrana: %s value %s is not a string", v.Type, raw.Value)
+		}
+		v.Value = s
+	}
+	return nil
+}
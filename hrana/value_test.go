@@ -0,0 +1,92 @@
+package hrana
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type upperValuer string
+
+func (v upperValuer) Value() (driver.Value, error) {
+	return "upper:" + string(v), nil
+}
+
+func TestEncodeValue(t *testing.T) {
+	n := 7
+	var nilPointer *int
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{nil, `{"type":"null"}`},
+		{int64(math.MaxInt64), `{"type":"integer","value":"9223372036854775807"}`},
+		{uint8(3), `{"type":"integer","value":"3"}`},
+		{true, `{"type":"integer","value":"1"}`},
+		{0.0, `{"type":"float","value":0}`},
+		{1.5, `{"type":"float","value":1.5}`},
+		{"hi", `{"type":"text","value":"hi"}`},
+		{[]byte("hi"), `{"type":"blob","base64":"aGk="}`},
+		{&n, `{"type":"integer","value":"7"}`},
+		{nilPointer, `{"type":"null"}`},
+		{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), `{"type":"text","value":"2024-01-02T03:04:05Z"}`},
+		{upperValuer("x"), `{"type":"text","value":"upper:x"}`},
+		{(*upperValuer)(nil), `{"type":"null"}`},
+		{(*sql.NullString)(nil), `{"type":"null"}`},
+		{&sql.NullString{String: "s", Valid: true}, `{"type":"text","value":"s"}`},
+	}
+	for _, test := range tests {
+		v, err := encodeValue(test.in)
+		if err != nil {
+			t.Errorf("encodeValue(%#v): %v", test.in, err)
+			continue
+		}
+		got, _ := json.Marshal(v)
+		if string(got) != test.want {
+			t.Errorf("encodeValue(%#v) = %s, want %s", test.in, got, test.want)
+		}
+	}
+	for _, invalid := range []interface{}{uint64(math.MaxUint64), math.NaN(), struct{}{}} {
+		if _, err := encodeValue(invalid); err == nil {
+			t.Errorf("encodeValue(%#v) should fail", invalid)
+		}
+	}
+}
+
+func TestDecodeValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want interface{}
+	}{
+		{`{"type":"null"}`, nil},
+		{`{"type":"integer","value":"-9223372036854775808"}`, int64(math.MinInt64)},
+		{`{"type":"float","value":2.5}`, 2.5},
+		{`{"type":"float","value":3}`, 3.0},
+		{`{"type":"text","value":""}`, ""},
+		{`{"type":"blob","base64":"aGk="}`, []byte("hi")},
+		{`{"type":"blob","base64":"aGk"}`, []byte("hi")},
+	}
+	for _, test := range tests {
+		var v value
+		if err := json.Unmarshal([]byte(test.in), &v); err != nil {
+			t.Errorf("unmarshal %s: %v", test.in, err)
+			continue
+		}
+		got, err := v.decode()
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("decode %s = %#v, %v, want %#v", test.in, got, err, test.want)
+		}
+	}
+	for _, invalid := range []string{`{"type":"integer","value":12}`, `{"type":"integer","value":"x"}`, `{"type":"date","value":"x"}`} {
+		var v value
+		if err := json.Unmarshal([]byte(invalid), &v); err == nil {
+			if _, err := v.decode(); err == nil {
+				t.Errorf("decoding %s should fail", invalid)
+			}
+		}
+	}
+}
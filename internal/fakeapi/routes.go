@@ -0,0 +1,818 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func (s *Server) buildRoutes() []route {
+	routes := []struct {
+		method  string
+		pattern string
+		handle  func(w http.ResponseWriter, r *http.Request, p params)
+	}{
+		{http.MethodGet, "/v1/auth/api-tokens", s.listAPITokens},
+		{http.MethodPost, "/v1/auth/api-tokens/{name}", s.mintAPIToken},
+		{http.MethodDelete, "/v1/auth/api-tokens/{name}", s.revokeAPIToken},
+		{http.MethodGet, "/v1/auth/validate", s.validateAPIToken},
+		{http.MethodGet, "/v1/locations", s.listLocations},
+		{http.MethodGet, "/region", s.closestRegion},
+		{http.MethodGet, "/v1/organizations", s.listOrganizations},
+		{http.MethodPatch, "/v1/organizations/{org}", s.updateOrganization},
+		{http.MethodPut, "/v1/organizations/{org}", s.updateOrganization},
+		{http.MethodGet, "/v1/organizations/{org}/members", s.listMembers},
+		{http.MethodPost, "/v1/organizations/{org}/members", s.addMember},
+		{http.MethodDelete, "/v1/organizations/{org}/members/{username}", s.removeMember},
+		{http.MethodGet, "/v1/organizations/{org}/invites", s.listInvites},
+		{http.MethodPost, "/v1/organizations/{org}/invites", s.createInvite},
+		{http.MethodPost, "/v1/organizations/{org}/databases/dumps", s.uploadDump},
+		{http.MethodGet, "/v1/organizations/{org}/databases", s.listDatabases},
+		{http.MethodPost, "/v1/organizations/{org}/databases", s.createDatabase},
+		{http.MethodGet, "/v1/organizations/{org}/databases/{db}", s.getDatabase},
+		{http.MethodDelete, "/v1/organizations/{org}/databases/{db}", s.deleteDatabase},
+		{http.MethodGet, "/v1/organizations/{org}/databases/{db}/configuration", s.getConfiguration},
+		{http.MethodPatch, "/v1/organizations/{org}/databases/{db}/configuration", s.updateConfiguration},
+		{http.MethodPost, "/v1/organizations/{org}/databases/{db}/update", s.updateDatabase},
+		{http.MethodPut, "/v1/organizations/{org}/databases/{db}/update", s.updateDatabase},
+		{http.MethodGet, "/v1/organizations/{org}/databases/{db}/usage", s.databaseUsage},
+		{http.MethodGet, "/v1/organizations/{org}/databases/{db}/stats", s.databaseStats},
+		{http.MethodGet, "/v1/organizations/{org}/databases/{db}/instances", s.listInstances},
+		{http.MethodPost, "/v1/organizations/{org}/databases/{db}/instances", s.createInstance},
+		{http.MethodGet, "/v1/organizations/{org}/databases/{db}/instances/{instance}", s.getInstance},
+		{http.MethodDelete, "/v1/organizations/{org}/databases/{db}/instances/{instance}", s.deleteInstance},
+		{http.MethodPost, "/v1/organizations/{org}/databases/{db}/auth/tokens", s.mintDatabaseToken},
+		{http.MethodDelete, "/v1/organizations/{org}/databases/{db}/auth/tokens", s.rotateDatabaseTokens},
+		{http.MethodPost, "/v1/organizations/{org}/databases/{db}/auth/rotate", s.rotateDatabaseTokens},
+		{http.MethodGet, "/v1/organizations/{org}/groups", s.listGroups},
+		{http.MethodPost, "/v1/organizations/{org}/groups", s.createGroup},
+		{http.MethodGet, "/v1/organizations/{org}/groups/{group}", s.getGroup},
+		{http.MethodDelete, "/v1/organizations/{org}/groups/{group}", s.deleteGroup},
+		{http.MethodPost, "/v1/organizations/{org}/groups/{group}/update", s.updateGroup},
+		{http.MethodPut, "/v1/organizations/{org}/groups/{group}/update", s.updateGroup},
+		{http.MethodPost, "/v1/organizations/{org}/groups/{group}/locations/{location}", s.addGroupLocation},
+		{http.MethodDelete, "/v1/organizations/{org}/groups/{group}/locations/{location}", s.removeGroupLocation},
+		{http.MethodPost, "/v1/organizations/{org}/groups/{group}/auth/tokens", s.mintGroupToken},
+		{http.MethodPost, "/v1/organizations/{org}/groups/{group}/auth/rotate", s.rotateGroupTokens},
+		{http.MethodPost, "/v1/organizations/{org}/groups/{group}/transfer", s.transferGroup},
+		{http.MethodGet, "/v1/organizations/{org}/plans", s.listPlans},
+		{http.MethodGet, "/v1/organizations/{org}/subscription", s.currentSubscription},
+		{http.MethodGet, "/v1/organizations/{org}/subscriptions", s.currentSubscription},
+		{http.MethodGet, "/v1/organizations/{org}/invoices", s.listInvoices},
+		{http.MethodGet, "/v1/organizations/{org}/usage", s.organizationUsage},
+		{http.MethodGet, "/v1/organizations/{org}/audit-logs", s.listAuditLogs},
+	}
+	built := make([]route, len(routes))
+	for i, r := range routes {
+		built[i] = route{method: r.method, pattern: strings.Split(strings.Trim(r.pattern, "/"), "/"), handle: r.handle}
+	}
+	return built
+}
+
+func (s *Server) org(w http.ResponseWriter, p params) (*orgState, bool) {
+	org, ok := s.orgs[p["org"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("organization %s not found", p["org"]))
+	}
+	return org, ok
+}
+
+func (s *Server) database(w http.ResponseWriter, p params) (*orgState, *database, bool) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return nil, nil, false
+	}
+	db, ok := org.databases[p["db"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("database %s not found", p["db"]))
+	}
+	return org, db, ok
+}
+
+func (s *Server) group(w http.ResponseWriter, p params) (*orgState, *group, bool) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return nil, nil, false
+	}
+	grp, ok := org.groups[p["group"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("group %s not found", p["group"]))
+	}
+	return org, grp, ok
+}
+
+func (s *Server) listAPITokens(w http.ResponseWriter, r *http.Request, p params) {
+	tokens := []apiToken{}
+	for _, name := range sortedKeys(s.apiTokens) {
+		token := s.apiTokens[name]
+		token.Token = ""
+		tokens = append(tokens, token)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tokens": tokens})
+}
+
+func (s *Server) mintAPIToken(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.apiTokens[p["name"]]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("token %s already exists", p["name"]))
+		return
+	}
+	token := apiToken{Name: p["name"], Id: newID(), Token: newID()}
+	s.apiTokens[token.Name] = token
+	writeJSON(w, http.StatusOK, token)
+}
+
+func (s *Server) revokeAPIToken(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.apiTokens[p["name"]]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("token %s not found", p["name"]))
+		return
+	}
+	delete(s.apiTokens, p["name"])
+	writeJSON(w, http.StatusOK, map[string]string{"token": p["name"]})
+}
+
+func (s *Server) validateAPIToken(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, map[string]int64{"exp": -1})
+}
+
+func (s *Server) listLocations(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"locations": s.locations})
+}
+
+func (s *Server) closestRegion(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, map[string]string{"server": DefaultLocation, "client": DefaultLocation})
+}
+
+func (s *Server) listOrganizations(w http.ResponseWriter, r *http.Request, p params) {
+	orgs := []organization{}
+	for _, slug := range sortedKeys(s.orgs) {
+		orgs = append(orgs, s.orgs[slug].organization)
+	}
+	writeJSON(w, http.StatusOK, orgs)
+}
+
+func (s *Server) updateOrganization(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	var body map[string]interface{}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if overages, ok := boolValue(body["overages"]); ok {
+		org.Overages = overages
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"organization": org.organization})
+}
+
+func (s *Server) listMembers(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	members := []map[string]string{}
+	for _, username := range sortedKeys(org.members) {
+		members = append(members, map[string]string{"username": username, "role": org.members[username]})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"members": members})
+}
+
+func (s *Server) addMember(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	var body struct {
+		Username string `json:"username"`
+		Role     string `json:"role"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Username == "" {
+		writeError(w, http.StatusBadRequest, "username is required")
+		return
+	}
+	if _, ok := org.members[body.Username]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("user %s is already a member", body.Username))
+		return
+	}
+	if body.Role == "" {
+		body.Role = "member"
+	}
+	org.members[body.Username] = body.Role
+	s.audit(org, "member-add", fmt.Sprintf("added %s as %s", body.Username, body.Role), map[string]interface{}{"username": body.Username, "role": body.Role})
+	writeJSON(w, http.StatusOK, map[string]interface{}{"member": body.Username, "role": body.Role})
+}
+
+func (s *Server) removeMember(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	if _, ok := org.members[p["username"]]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("user %s is not a member", p["username"]))
+		return
+	}
+	delete(org.members, p["username"])
+	s.audit(org, "member-remove", fmt.Sprintf("removed %s", p["username"]), map[string]interface{}{"username": p["username"]})
+	writeJSON(w, http.StatusOK, map[string]string{"member": p["username"]})
+}
+
+func (s *Server) listInvites(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	invites := append([]invite{}, org.invites...)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"invites": invites})
+}
+
+func (s *Server) createInvite(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	var body struct {
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Email == "" {
+		writeError(w, http.StatusBadRequest, "email is required")
+		return
+	}
+	if body.Role == "" {
+		body.Role = "member"
+	}
+	now := s.now().UTC().Format("2006-01-02T15:04:05Z")
+	inv := invite{
+		ID:           len(org.invites) + 1,
+		Email:        body.Email,
+		Role:         body.Role,
+		Token:        newID(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Organization: org.organization,
+	}
+	org.invites = append(org.invites, inv)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"invited": inv})
+}
+
+func (s *Server) uploadDump(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.org(w, p); !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"dump_url": fmt.Sprintf("%s/dumps/%s", s.URL, newID())})
+}
+
+func (s *Server) listDatabases(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	databases := []*database{}
+	for _, name := range sortedKeys(org.databases) {
+		databases = append(databases, org.databases[name])
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"databases": databases})
+}
+
+func (s *Server) createDatabase(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	var body struct {
+		Name string `json:"name"`
+		// Group is optional for organizations that only have one group.
+		Group string `json:"group"`
+		Seed  *struct {
+			Type string `json:"type"`
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"seed"`
+		SizeLimit string `json:"size_limit"`
+		IsSchema  bool   `json:"is_schema"`
+		Schema    string `json:"schema"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Name == "" {
+		writeError(w, http.StatusBadRequest, "database name is required")
+		return
+	}
+	if _, ok := org.databases[body.Name]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("database %s already exists", body.Name))
+		return
+	}
+	if body.Group == "" && len(org.groups) == 1 {
+		body.Group = sortedKeys(org.groups)[0]
+	}
+	grp, ok := org.groups[body.Group]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("group %s not found", body.Group))
+		return
+	}
+	if body.Seed != nil && body.Seed.Type == "database" {
+		if _, ok := org.databases[body.Seed.Name]; !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("seed database %s not found", body.Seed.Name))
+			return
+		}
+	}
+	if body.Schema != "" {
+		if schema, ok := org.databases[body.Schema]; !ok || !schema.IsSchema {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("schema database %s not found", body.Schema))
+			return
+		}
+	}
+	db := s.newDatabase(org, grp, body.Name)
+	db.IsSchema = body.IsSchema
+	db.Schema = body.Schema
+	db.SizeLimit = body.SizeLimit
+	org.databases[db.Name] = db
+	s.audit(org, "db-create", fmt.Sprintf("created database %s", db.Name), map[string]interface{}{"name": db.Name, "group": db.Group})
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"database": map[string]string{"DbId": db.DbId, "Hostname": db.Hostname, "Name": db.Name},
+	})
+}
+
+func (s *Server) getDatabase(w http.ResponseWriter, r *http.Request, p params) {
+	if _, db, ok := s.database(w, p); ok {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"database": db})
+	}
+}
+
+func (s *Server) deleteDatabase(w http.ResponseWriter, r *http.Request, p params) {
+	org, db, ok := s.database(w, p)
+	if !ok {
+		return
+	}
+	delete(org.databases, db.Name)
+	s.audit(org, "db-delete", fmt.Sprintf("deleted database %s", db.Name), map[string]interface{}{"name": db.Name})
+	writeJSON(w, http.StatusOK, map[string]string{"database": db.Name})
+}
+
+func (s *Server) configuration(db *database) map[string]interface{} {
+	return map[string]interface{}{
+		"size_limit":   db.SizeLimit,
+		"allow_attach": db.AllowAttach,
+		"block_reads":  db.BlockReads,
+		"block_writes": db.BlockWrites,
+	}
+}
+
+func (s *Server) getConfiguration(w http.ResponseWriter, r *http.Request, p params) {
+	if _, db, ok := s.database(w, p); ok {
+		writeJSON(w, http.StatusOK, s.configuration(db))
+	}
+}
+
+func (s *Server) updateConfiguration(w http.ResponseWriter, r *http.Request, p params) {
+	_, db, ok := s.database(w, p)
+	if !ok {
+		return
+	}
+	var body map[string]interface{}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if sizeLimit, ok := body["size_limit"].(string); ok {
+		db.SizeLimit = sizeLimit
+	}
+	if value, ok := boolValue(body["allow_attach"]); ok {
+		db.AllowAttach = value
+	}
+	if value, ok := boolValue(body["block_reads"]); ok {
+		db.BlockReads = value
+	}
+	if value, ok := boolValue(body["block_writes"]); ok {
+		db.BlockWrites = value
+	}
+	writeJSON(w, http.StatusOK, s.configuration(db))
+}
+
+func (s *Server) updateDatabase(w http.ResponseWriter, r *http.Request, p params) {
+	if _, _, ok := s.database(w, p); ok {
+		writeJSON(w, http.StatusOK, map[string]string{})
+	}
+}
+
+func (s *Server) databaseUsage(w http.ResponseWriter, r *http.Request, p params) {
+	_, db, ok := s.database(w, p)
+	if !ok {
+		return
+	}
+	usage := map[string]int{"rows_read": 0, "rows_written": 0, "storage_bytes": 0}
+	instances := []map[string]interface{}{}
+	for _, name := range sortedKeys(db.instances) {
+		instances = append(instances, map[string]interface{}{"uuid": db.instances[name].UUID, "usage": usage})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"database": map[string]interface{}{"uuid": db.DbId, "instances": instances, "total": usage},
+	})
+}
+
+func (s *Server) databaseStats(w http.ResponseWriter, r *http.Request, p params) {
+	if _, _, ok := s.database(w, p); ok {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"top_queries": []interface{}{}})
+	}
+}
+
+func (s *Server) listInstances(w http.ResponseWriter, r *http.Request, p params) {
+	_, db, ok := s.database(w, p)
+	if !ok {
+		return
+	}
+	instances := []*instance{}
+	for _, name := range sortedKeys(db.instances) {
+		instances = append(instances, db.instances[name])
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"instances": instances})
+}
+
+func (s *Server) createInstance(w http.ResponseWriter, r *http.Request, p params) {
+	_, db, ok := s.database(w, p)
+	if !ok {
+		return
+	}
+	var body struct {
+		Location string `json:"location"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if _, ok := s.locations[body.Location]; !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid location %s", body.Location))
+		return
+	}
+	if _, ok := db.instances[body.Location]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("instance %s already exists", body.Location))
+		return
+	}
+	inst := &instance{
+		UUID:     newID(),
+		Name:     body.Location,
+		Type:     "replica",
+		Region:   body.Location,
+		Hostname: fmt.Sprintf("%s-%s", body.Location, db.Hostname),
+	}
+	db.instances[inst.Name] = inst
+	db.Regions = append(db.Regions, body.Location)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"instance": inst})
+}
+
+func (s *Server) getInstance(w http.ResponseWriter, r *http.Request, p params) {
+	_, db, ok := s.database(w, p)
+	if !ok {
+		return
+	}
+	inst, ok := db.instances[p["instance"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("instance %s not found", p["instance"]))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"instance": inst})
+}
+
+func (s *Server) deleteInstance(w http.ResponseWriter, r *http.Request, p params) {
+	_, db, ok := s.database(w, p)
+	if !ok {
+		return
+	}
+	inst, ok := db.instances[p["instance"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("instance %s not found", p["instance"]))
+		return
+	}
+	if inst.Type == "primary" {
+		writeError(w, http.StatusBadRequest, "cannot delete the primary instance")
+		return
+	}
+	delete(db.instances, inst.Name)
+	db.Regions = removeString(db.Regions, inst.Region)
+	writeJSON(w, http.StatusOK, map[string]string{"instance": inst.Name})
+}
+
+func (s *Server) mintDatabaseToken(w http.ResponseWriter, r *http.Request, p params) {
+	org, db, ok := s.database(w, p)
+	if !ok {
+		return
+	}
+	s.mintToken(w, r, org, map[string]interface{}{"id": db.DbId}, map[string]interface{}{"database": db.Name})
+}
+
+func (s *Server) mintGroupToken(w http.ResponseWriter, r *http.Request, p params) {
+	org, grp, ok := s.group(w, p)
+	if !ok {
+		return
+	}
+	s.mintToken(w, r, org, map[string]interface{}{"gid": grp.UUID}, map[string]interface{}{"group": grp.Name})
+}
+
+// mintToken answers a token request, checking that the databases of its
+// optional fine-grained permissions exist.
+func (s *Server) mintToken(w http.ResponseWriter, r *http.Request, org *orgState, claims, data map[string]interface{}) {
+	var body struct {
+		Permissions struct {
+			ReadAttach struct {
+				Databases []string `json:"databases"`
+			} `json:"read_attach"`
+		} `json:"permissions"`
+	}
+	if r.ContentLength != 0 && !decodeBody(w, r, &body) {
+		return
+	}
+	if attach := body.Permissions.ReadAttach.Databases; len(attach) > 0 {
+		for _, name := range attach {
+			if _, ok := org.databases[name]; !ok {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("database %s not found", name))
+				return
+			}
+		}
+		claims["p"] = map[string]interface{}{"roa": map[string]interface{}{"ns": attach}}
+	}
+	query := r.URL.Query()
+	jwt, err := s.mintJWT(claims, query.Get("expiration"), query.Get("authorization"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data["authorization"] = query.Get("authorization")
+	data["expiration"] = query.Get("expiration")
+	target := data["database"]
+	if target == nil {
+		target = data["group"]
+	}
+	s.audit(org, "token-mint", fmt.Sprintf("minted a token for %s", target), data)
+	writeJSON(w, http.StatusOK, map[string]string{"jwt": jwt})
+}
+
+func (s *Server) rotateDatabaseTokens(w http.ResponseWriter, r *http.Request, p params) {
+	if _, _, ok := s.database(w, p); ok {
+		writeJSON(w, http.StatusOK, map[string]string{})
+	}
+}
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	groups := []*group{}
+	for _, name := range sortedKeys(org.groups) {
+		groups = append(groups, org.groups[name])
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"groups": groups})
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	var body struct {
+		Name     string `json:"name"`
+		Location string `json:"location"`
+		Version  string `json:"version"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Name == "" {
+		writeError(w, http.StatusBadRequest, "group name is required")
+		return
+	}
+	if _, ok := s.locations[body.Location]; !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid location %s", body.Location))
+		return
+	}
+	if _, ok := org.groups[body.Name]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("group %s already exists", body.Name))
+		return
+	}
+	grp := &group{Name: body.Name, UUID: newID(), Primary: body.Location, Locations: []string{body.Location}, Version: body.Version}
+	org.groups[grp.Name] = grp
+	s.audit(org, "group-create", fmt.Sprintf("created group %s", grp.Name), map[string]interface{}{"name": grp.Name, "location": grp.Primary})
+	writeJSON(w, http.StatusOK, map[string]interface{}{"group": grp})
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *http.Request, p params) {
+	if _, grp, ok := s.group(w, p); ok {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"group": grp})
+	}
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request, p params) {
+	org, grp, ok := s.group(w, p)
+	if !ok {
+		return
+	}
+	for name, db := range org.databases {
+		if db.Group == grp.Name {
+			delete(org.databases, name)
+		}
+	}
+	delete(org.groups, grp.Name)
+	s.audit(org, "group-delete", fmt.Sprintf("deleted group %s", grp.Name), map[string]interface{}{"name": grp.Name})
+	writeJSON(w, http.StatusOK, map[string]interface{}{"group": grp})
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request, p params) {
+	if _, _, ok := s.group(w, p); ok {
+		writeJSON(w, http.StatusOK, map[string]string{})
+	}
+}
+
+func (s *Server) addGroupLocation(w http.ResponseWriter, r *http.Request, p params) {
+	org, grp, ok := s.group(w, p)
+	if !ok {
+		return
+	}
+	location := p["location"]
+	if _, ok := s.locations[location]; !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid location %s", location))
+		return
+	}
+	if containsString(grp.Locations, location) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("group %s is already in %s", grp.Name, location))
+		return
+	}
+	grp.Locations = append(grp.Locations, location)
+	for _, db := range org.databases {
+		if db.Group == grp.Name {
+			db.Regions = append(db.Regions, location)
+			db.instances[location] = &instance{
+				UUID:     newID(),
+				Name:     location,
+				Type:     "replica",
+				Region:   location,
+				Hostname: fmt.Sprintf("%s-%s", location, db.Hostname),
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"group": grp})
+}
+
+func (s *Server) removeGroupLocation(w http.ResponseWriter, r *http.Request, p params) {
+	org, grp, ok := s.group(w, p)
+	if !ok {
+		return
+	}
+	location := p["location"]
+	if !containsString(grp.Locations, location) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("group %s is not in %s", grp.Name, location))
+		return
+	}
+	if location == grp.Primary {
+		writeError(w, http.StatusBadRequest, "cannot remove the primary location")
+		return
+	}
+	grp.Locations = removeString(grp.Locations, location)
+	for _, db := range org.databases {
+		if db.Group == grp.Name {
+			db.Regions = removeString(db.Regions, location)
+			delete(db.instances, location)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"group": grp})
+}
+
+func (s *Server) rotateGroupTokens(w http.ResponseWriter, r *http.Request, p params) {
+	if _, _, ok := s.group(w, p); ok {
+		writeJSON(w, http.StatusOK, map[string]string{})
+	}
+}
+
+func (s *Server) transferGroup(w http.ResponseWriter, r *http.Request, p params) {
+	org, grp, ok := s.group(w, p)
+	if !ok {
+		return
+	}
+	var body struct {
+		Organization string `json:"organization"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	target, ok := s.orgs[body.Organization]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("organization %s not found", body.Organization))
+		return
+	}
+	if _, ok := target.groups[grp.Name]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("group %s already exists in %s", grp.Name, target.Slug))
+		return
+	}
+	delete(org.groups, grp.Name)
+	target.groups[grp.Name] = grp
+	for name, db := range org.databases {
+		if db.Group == grp.Name {
+			delete(org.databases, name)
+			target.databases[name] = db
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"group": grp})
+}
+
+func (s *Server) listPlans(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.org(w, p); ok {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"plans": []interface{}{}})
+	}
+}
+
+func (s *Server) currentSubscription(w http.ResponseWriter, r *http.Request, p params) {
+	if org, ok := s.org(w, p); ok {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"subscription": "starter", "overages": org.Overages, "plan": "starter", "timeline": "monthly",
+		})
+	}
+}
+
+func (s *Server) listInvoices(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.org(w, p); ok {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"invoices": []interface{}{}})
+	}
+}
+
+func (s *Server) organizationUsage(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	locations := map[string]bool{}
+	for _, db := range org.databases {
+		for _, region := range db.Regions {
+			locations[region] = true
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"organization": map[string]interface{}{
+			"uuid": org.Slug,
+			"usage": map[string]int{
+				"rows_read":    0,
+				"rows_written": 0,
+				"databases":    len(org.databases),
+				"locations":    len(locations),
+				"storage":      0,
+				"groups":       len(org.groups),
+				"bytes_synced": 0,
+			},
+		},
+	})
+}
+
+func (s *Server) listAuditLogs(w http.ResponseWriter, r *http.Request, p params) {
+	org, ok := s.org(w, p)
+	if !ok {
+		return
+	}
+	page := queryInt(r, "page", 1)
+	pageSize := queryInt(r, "page_size", 10)
+	total := len(org.auditLogs)
+	logs := []auditLog{}
+	for i := (page - 1) * pageSize; i < page*pageSize && i < total; i++ {
+		logs = append(logs, org.auditLogs[total-1-i])
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"audit_logs": logs,
+		"pagination": map[string]int{
+			"page":        page,
+			"page_size":   pageSize,
+			"total_pages": (total + pageSize - 1) / pageSize,
+			"total_rows":  total,
+		},
+	})
+}
+
+func boolValue(v interface{}) (bool, bool) {
+	switch value := v.(type) {
+	case bool:
+		return value, true
+	case string:
+		return value == "true", value == "true" || value == "false"
+	}
+	return false, false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(values []string, value string) []string {
+	kept := values[:0]
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
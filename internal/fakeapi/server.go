@@ -0,0 +1,377 @@
+// Package fakeapi implements a stateful, in-process fake of the Turso
+// platform API. It does not depend on the client package so that the
+// client's own tests can use it; turstest exposes it to users of the SDK.
+package fakeapi
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultToken is the API token accepted by a new Server.
+	DefaultToken = "turstest-token"
+	// DefaultOrganization is the slug of the organization a new Server
+	// starts with. It has a "default" group in DefaultLocation.
+	DefaultOrganization = "turstest"
+	DefaultGroup        = "default"
+	DefaultLocation     = "ams"
+	// Username is the author of every audit log written by the Server.
+	Username = "turstest"
+)
+
+// Server is an httptest server faking the Turso platform API. Creates and
+// deletes are reflected in later reads, requests must carry the server's
+// API token and errors are returned with the API's {"error": "..."} body.
+type Server struct {
+	*httptest.Server
+	// Token is the API token the server accepts.
+	Token string
+
+	mu         sync.Mutex
+	now        func() time.Time
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+	locations  map[string]string
+	orgs       map[string]*orgState
+	apiTokens  map[string]apiToken
+	routes     []route
+}
+
+type organization struct {
+	Name          string `json:"name"`
+	Slug          string `json:"slug"`
+	Type          string `json:"type"`
+	BlockedReads  bool   `json:"blocked_reads"`
+	BlockedWrites bool   `json:"blocked_writes"`
+	Overages      bool   `json:"overages"`
+}
+
+type orgState struct {
+	organization
+	databases map[string]*database
+	groups    map[string]*group
+	members   map[string]string
+	invites   []invite
+	auditLogs []auditLog
+}
+
+type database struct {
+	Name          string   `json:"Name"`
+	DbId          string   `json:"DbId"`
+	Hostname      string   `json:"Hostname"`
+	Regions       []string `json:"regions"`
+	PrimaryRegion string   `json:"primaryRegion"`
+	Type          string   `json:"type"`
+	Version       string   `json:"version"`
+	Group         string   `json:"group"`
+	IsSchema      bool     `json:"is_schema"`
+	Schema        string   `json:"schema"`
+	AllowAttach   bool     `json:"allow_attach"`
+	BlockReads    bool     `json:"block_reads"`
+	BlockWrites   bool     `json:"block_writes"`
+	SizeLimit     string   `json:"-"`
+
+	instances map[string]*instance
+}
+
+type instance struct {
+	UUID     string `json:"uuid"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Region   string `json:"region"`
+	Hostname string `json:"hostname"`
+}
+
+type group struct {
+	Name      string   `json:"name"`
+	UUID      string   `json:"uuid"`
+	Primary   string   `json:"primary"`
+	Locations []string `json:"locations"`
+	Archived  bool     `json:"archived"`
+	Version   string   `json:"version"`
+}
+
+type invite struct {
+	ID             int          `json:"Id"`
+	Email          string       `json:"Email"`
+	Role           string       `json:"Role"`
+	Token          string       `json:"Token"`
+	Accepted       bool         `json:"Accepted"`
+	CreatedAt      string       `json:"CreatedAt"`
+	UpdatedAt      string       `json:"UpdatedAt"`
+	Organization   organization `json:"Organization"`
+	OrganizationID int          `json:"OrganizationID"`
+}
+
+type auditLog struct {
+	Author    string                 `json:"author"`
+	Code      string                 `json:"code"`
+	CreatedAt string                 `json:"created_at"`
+	Data      map[string]interface{} `json:"data"`
+	Message   string                 `json:"message"`
+	Origin    string                 `json:"origin"`
+}
+
+type apiToken struct {
+	Name  string `json:"name"`
+	Id    string `json:"id"`
+	Token string `json:"token,omitempty"`
+}
+
+// NewServer starts a Server with DefaultOrganization and the default
+// locations. The caller must Close it.
+func NewServer() *Server {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(fmt.Sprintf("fakeapi: generating signing key: %v", err))
+	}
+	s := &Server{
+		Token:      DefaultToken,
+		now:        time.Now,
+		privateKey: privateKey,
+		publicKey:  publicKey,
+		locations: map[string]string{
+			"ams": "Amsterdam, Netherlands",
+			"fra": "Frankfurt, Germany",
+			"iad": "Ashburn, Virginia (US)",
+			"lhr": "London, United Kingdom",
+			"nrt": "Tokyo, Japan",
+			"sjc": "San Jose, California (US)",
+		},
+		orgs:      map[string]*orgState{},
+		apiTokens: map[string]apiToken{},
+	}
+	s.routes = s.buildRoutes()
+	s.AddOrganization(DefaultOrganization)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// RegionURL is the URL answering the closest region lookup.
+func (s *Server) RegionURL() string {
+	return s.URL + "/region"
+}
+
+// PublicKey is the Ed25519 key verifying the database tokens minted by the
+// server.
+func (s *Server) PublicKey() ed25519.PublicKey {
+	return s.publicKey
+}
+
+// SetClock replaces the function used to timestamp audit logs and tokens.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// AddOrganization adds an organization with a "default" group, unless it
+// already exists.
+func (s *Server) AddOrganization(slug string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.orgs[slug]; ok {
+		return
+	}
+	s.orgs[slug] = &orgState{
+		organization: organization{Name: slug, Slug: slug, Type: "personal"},
+		databases:    map[string]*database{},
+		groups: map[string]*group{
+			DefaultGroup: {Name: DefaultGroup, UUID: newID(), Primary: DefaultLocation, Locations: []string{DefaultLocation}},
+		},
+		members: map[string]string{Username: "owner"},
+	}
+}
+
+// AddDatabase adds a database to the group of an organization, creating
+// both when they do not exist, and returns its ID.
+func (s *Server) AddDatabase(orgSlug, groupName, dbName string) string {
+	s.AddOrganization(orgSlug)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org := s.orgs[orgSlug]
+	grp, ok := org.groups[groupName]
+	if !ok {
+		grp = &group{Name: groupName, UUID: newID(), Primary: DefaultLocation, Locations: []string{DefaultLocation}}
+		org.groups[groupName] = grp
+	}
+	if db, ok := org.databases[dbName]; ok {
+		return db.DbId
+	}
+	db := s.newDatabase(org, grp, dbName)
+	org.databases[dbName] = db
+	return db.DbId
+}
+
+// AddInstance adds an instance of a database in location.
+func (s *Server) AddInstance(orgSlug, dbName, instanceName, location string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org, ok := s.orgs[orgSlug]
+	if !ok {
+		return
+	}
+	if db, ok := org.databases[dbName]; ok {
+		db.instances[instanceName] = &instance{
+			UUID:     newID(),
+			Name:     instanceName,
+			Type:     "replica",
+			Region:   location,
+			Hostname: fmt.Sprintf("%s-%s", location, db.Hostname),
+		}
+	}
+}
+
+// AddAuditLog appends an audit log to an organization.
+func (s *Server) AddAuditLog(orgSlug, code, message string, data map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if org, ok := s.orgs[orgSlug]; ok {
+		s.audit(org, code, message, data)
+	}
+}
+
+func (s *Server) newDatabase(org *orgState, grp *group, name string) *database {
+	db := &database{
+		Name:          name,
+		DbId:          newID(),
+		Hostname:      fmt.Sprintf("%s-%s.turso.io", name, org.Slug),
+		Regions:       append([]string(nil), grp.Locations...),
+		PrimaryRegion: grp.Primary,
+		Type:          "logical",
+		Version:       "0.24.0",
+		Group:         grp.Name,
+		instances:     map[string]*instance{},
+	}
+	for _, location := range grp.Locations {
+		instanceType := "replica"
+		if location == grp.Primary {
+			instanceType = "primary"
+		}
+		db.instances[location] = &instance{
+			UUID:     newID(),
+			Name:     location,
+			Type:     instanceType,
+			Region:   location,
+			Hostname: fmt.Sprintf("%s-%s", location, db.Hostname),
+		}
+	}
+	return db
+}
+
+func (s *Server) audit(org *orgState, code, message string, data map[string]interface{}) {
+	org.auditLogs = append(org.auditLogs, auditLog{
+		Author:    Username,
+		Code:      code,
+		CreatedAt: s.now().UTC().Format(time.RFC3339),
+		Data:      data,
+		Message:   message,
+		Origin:    "api",
+	})
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("fakeapi: generating id: %v", err))
+	}
+	return fmt.Sprintf("%s-%s-%s-%s-%s", hex.EncodeToString(b[0:4]), hex.EncodeToString(b[4:6]),
+		hex.EncodeToString(b[6:8]), hex.EncodeToString(b[8:10]), hex.EncodeToString(b[10:]))
+}
+
+type params map[string]string
+
+type route struct {
+	method  string
+	pattern []string
+	handle  func(w http.ResponseWriter, r *http.Request, p params)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "token is invalid or has expired")
+		return
+	}
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	methodAllowed := false
+	for _, route := range s.routes {
+		p, ok := match(route.pattern, path)
+		if !ok {
+			continue
+		}
+		if route.method != r.Method {
+			methodAllowed = true
+			continue
+		}
+		s.mu.Lock()
+		route.handle(w, r, p)
+		s.mu.Unlock()
+		return
+	}
+	if methodAllowed {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeError(w, http.StatusNotFound, "not found")
+}
+
+func match(pattern, path []string) (params, bool) {
+	if len(pattern) != len(path) {
+		return nil, false
+	}
+	p := params{}
+	for i, segment := range pattern {
+		if strings.HasPrefix(segment, "{") {
+			p[strings.Trim(segment, "{}")] = path[i]
+		} else if segment != path[i] {
+			return nil, false
+		}
+	}
+	return p, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func queryInt(r *http.Request, name string, fallback int) int {
+	value, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil || value < 1 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,71 @@
+package fakeapi
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// mintJWT signs a database token the way the platform does: an EdDSA JWT
+// whose "a" claim is the access level and "id" or "gid" the database or
+// group it is scoped to.
+func (s *Server) mintJWT(claims map[string]interface{}, expiration, authorization string) (string, error) {
+	now := s.now()
+	claims["iat"] = now.Unix()
+	switch authorization {
+	case "", "full-access":
+		claims["a"] = "rw"
+	case "read-only":
+		claims["a"] = "ro"
+	default:
+		return "", fmt.Errorf("invalid authorization %q", authorization)
+	}
+	if expiration != "" && expiration != "never" {
+		ttl, err := parseExpiration(expiration)
+		if err != nil {
+			return "", err
+		}
+		claims["exp"] = now.Add(ttl).Unix()
+	}
+	header, _ := json.Marshal(map[string]string{"alg": "EdDSA", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(s.privateKey, []byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseExpiration parses expirations such as "2w1d30m" made of w, d, h, m
+// and s units.
+func parseExpiration(value string) (time.Duration, error) {
+	units := map[byte]time.Duration{
+		'w': 7 * 24 * time.Hour,
+		'd': 24 * time.Hour,
+		'h': time.Hour,
+		'm': time.Minute,
+		's': time.Second,
+	}
+	var total time.Duration
+	start := 0
+	for i := 0; i < len(value); i++ {
+		unit, ok := units[value[i]]
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(value[start:i])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid expiration %q", value)
+		}
+		total += time.Duration(n) * unit
+		start = i + 1
+	}
+	if start != len(value) || total == 0 {
+		return 0, fmt.Errorf("invalid expiration %q", value)
+	}
+	return total, nil
+}
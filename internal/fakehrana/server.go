@@ -0,0 +1,558 @@
+// Package fakehrana implements an in-process fake of the Hrana HTTP
+// protocol spoken by Turso databases. Statements are answered by handlers
+// registered per SQL string, since there is no SQL engine behind it. It does
+// not depend on the hrana package so that the package's own tests can use
+// it; turstest exposes it to users of the SDK.
+package fakehrana
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultToken is the database token accepted by a new Server.
+const DefaultToken = "turstest-db-token"
+
+// Stmt is a statement received by the server, with its arguments decoded to
+// int64, float64, string, []byte or nil.
+type Stmt struct {
+	SQL       string
+	Args      []interface{}
+	NamedArgs map[string]interface{}
+}
+
+// Result is the result of a statement. Row values may be int, int64,
+// float64, string, []byte or nil.
+type Result struct {
+	Columns          []string
+	Rows             [][]interface{}
+	AffectedRowCount int64
+	LastInsertRowID  int64
+}
+
+// Error fails a statement with a SQLite error code such as
+// "SQLITE_CONSTRAINT".
+type Error struct {
+	Message string
+	Code    string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Handler answers a statement. Returning an *Error sets its code; other
+// errors are reported as SQLITE_ERROR.
+type Handler func(stmt Stmt) (*Result, error)
+
+// Returns is a Handler always answering result.
+func Returns(result *Result) Handler {
+	return func(Stmt) (*Result, error) {
+		return result, nil
+	}
+}
+
+// Server is an httptest server faking the Hrana pipeline and cursor
+// endpoints of a database. Requests must carry the server's token. Streams are tracked by
+// baton: every baton can be used once, and BEGIN, COMMIT and ROLLBACK
+// statements open and close transactions on their stream.
+type Server struct {
+	*httptest.Server
+	// Token is the database token the server accepts.
+	Token string
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	executed []Stmt
+	streams  map[string]*stream
+	primary  string
+}
+
+type stream struct {
+	inTx bool
+}
+
+// NewServer starts a Server. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		Token:    DefaultToken,
+		handlers: map[string]Handler{},
+		streams:  map[string]*stream{},
+	}
+	s.Server = httptest.NewServer(s.authenticate(http.HandlerFunc(s.route)))
+	return s
+}
+
+// SetPrimary makes the server behave like a replica whose primary is served
+// under path, such as "/primary": streams opened elsewhere are redirected to
+// the primary with the base_url of their responses, and continuing a stream
+// anywhere but on the primary fails.
+func (s *Server) SetPrimary(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.primary = strings.TrimSuffix(path, "/")
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	primary := s.primary
+	s.mu.Unlock()
+	path := r.URL.Path
+	onPrimary := primary == ""
+	if primary != "" && strings.HasPrefix(path, primary+"/") {
+		path, onPrimary = strings.TrimPrefix(path, primary), true
+	}
+	switch path {
+	case "/v2/pipeline", "/v3/pipeline":
+		s.pipeline(w, r, onPrimary)
+	case "/v3/cursor":
+		s.cursor(w, r, onPrimary)
+	default:
+		writeError(w, http.StatusNotFound, "not found", "")
+	}
+}
+
+// Handle registers the handler of a statement. SQL is matched after
+// trimming spaces and a trailing semicolon.
+func (s *Server) Handle(sql string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[normalize(sql)] = handler
+}
+
+// Executed returns the statements executed so far, in order.
+func (s *Server) Executed() []Stmt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Stmt(nil), s.executed...)
+}
+
+// OpenStreams returns the number of streams that have not been closed.
+func (s *Server) OpenStreams() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.streams)
+}
+
+func normalize(sql string) string {
+	return strings.TrimSuffix(strings.Join(strings.Fields(sql), " "), ";")
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+s.Token {
+			writeError(w, http.StatusUnauthorized, "Unauthorized", "AUTH_JWT_INVALID")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type value struct {
+	Type   string      `json:"type"`
+	Value  interface{} `json:"value,omitempty"`
+	Base64 string      `json:"base64,omitempty"`
+}
+
+type stmt struct {
+	SQL       string  `json:"sql"`
+	Args      []value `json:"args"`
+	NamedArgs []struct {
+		Name  string `json:"name"`
+		Value value  `json:"value"`
+	} `json:"named_args"`
+	WantRows *bool `json:"want_rows"`
+}
+
+type condition struct {
+	Type  string      `json:"type"`
+	Step  int         `json:"step"`
+	Cond  *condition  `json:"cond"`
+	Conds []condition `json:"conds"`
+}
+
+type batch struct {
+	Steps []struct {
+		Condition *condition `json:"condition"`
+		Stmt      stmt       `json:"stmt"`
+	} `json:"steps"`
+}
+
+type request struct {
+	Type  string `json:"type"`
+	Stmt  *stmt  `json:"stmt"`
+	Batch *batch `json:"batch"`
+}
+
+type errorBody struct {
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message, code string) {
+	writeJSON(w, status, errorBody{Message: message, Code: code})
+}
+
+func (s *Server) pipeline(w http.ResponseWriter, r *http.Request, onPrimary bool) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+		return
+	}
+	var body struct {
+		Baton    *string   `json:"baton"`
+		Requests []request `json:"requests"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), "")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.takeStream(body.Baton, onPrimary)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "The stream has expired or the baton is invalid", "STREAM_EXPIRED")
+		return
+	}
+	closed := false
+	results := make([]interface{}, len(body.Requests))
+	for i, req := range body.Requests {
+		if closed {
+			results[i] = errorResult("the stream is closed", "STREAM_CLOSED")
+			continue
+		}
+		switch req.Type {
+		case "execute":
+			if req.Stmt == nil {
+				results[i] = errorResult("missing stmt", "")
+				continue
+			}
+			result, err := s.execute(st, *req.Stmt)
+			if err != nil {
+				results[i] = map[string]interface{}{"type": "error", "error": err}
+				continue
+			}
+			results[i] = okResult("execute", result)
+		case "batch":
+			if req.Batch == nil {
+				results[i] = errorResult("missing batch", "")
+				continue
+			}
+			stepResults, stepErrors := s.runBatch(st, req.Batch)
+			results[i] = okResult("batch", map[string]interface{}{"step_results": stepResults, "step_errors": stepErrors})
+		case "get_autocommit":
+			results[i] = okResult("get_autocommit", map[string]bool{"is_autocommit": !st.inTx})
+		case "close":
+			closed = true
+			results[i] = map[string]interface{}{"type": "ok", "response": map[string]string{"type": "close"}}
+		default:
+			results[i] = errorResult(fmt.Sprintf("fakehrana: request type %q is not supported", req.Type), "")
+		}
+	}
+
+	var baton, baseURL interface{}
+	if !closed {
+		baton, baseURL = s.continueStream(st, onPrimary)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"baton": baton, "base_url": baseURL, "results": results})
+}
+
+// cursor runs a batch and streams its results as newline-delimited cursor
+// entries, flushing each one, so that clients read rows as they arrive and
+// a client that stops reading blocks the server.
+func (s *Server) cursor(w http.ResponseWriter, r *http.Request, onPrimary bool) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+		return
+	}
+	var body struct {
+		Baton *string `json:"baton"`
+		Batch *batch  `json:"batch"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Batch == nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", "")
+		return
+	}
+
+	s.mu.Lock()
+	st, ok := s.takeStream(body.Baton, onPrimary)
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "The stream has expired or the baton is invalid", "STREAM_EXPIRED")
+		return
+	}
+	stepResults, stepErrors := s.runBatch(st, body.Batch)
+	baton, baseURL := s.continueStream(st, onPrimary)
+	s.mu.Unlock()
+
+	var entries []interface{}
+	for i := range body.Batch.Steps {
+		if err := stepErrors[i]; err != nil {
+			entries = append(entries, map[string]interface{}{"type": "step_error", "step": i, "error": err})
+			continue
+		}
+		result, ok := stepResults[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		entries = append(entries, map[string]interface{}{"type": "step_begin", "step": i, "cols": result["cols"]})
+		for _, row := range result["rows"].([][]value) {
+			entries = append(entries, map[string]interface{}{"type": "row", "row": row})
+		}
+		entries = append(entries, map[string]interface{}{
+			"type":               "step_end",
+			"affected_row_count": result["affected_row_count"],
+			"last_insert_rowid":  result["last_insert_rowid"],
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	entries = append([]interface{}{map[string]interface{}{"baton": baton, "base_url": baseURL}}, entries...)
+	for _, entry := range entries {
+		if enc.Encode(entry) != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// takeStream returns the stream continued by baton, or a new stream when
+// baton is nil, and invalidates the baton. The caller holds mu.
+func (s *Server) takeStream(baton *string, onPrimary bool) (*stream, bool) {
+	if baton == nil {
+		return &stream{}, true
+	}
+	st, ok := s.streams[*baton]
+	if !ok || !onPrimary {
+		return nil, false
+	}
+	delete(s.streams, *baton)
+	return st, true
+}
+
+// continueStream issues the baton continuing st, and the base URL it must
+// be continued on when the request did not reach the primary. The caller
+// holds mu.
+func (s *Server) continueStream(st *stream, onPrimary bool) (baton, baseURL interface{}) {
+	next := newBaton()
+	s.streams[next] = st
+	if !onPrimary {
+		baseURL = s.URL + s.primary
+	}
+	return next, baseURL
+}
+
+// runBatch runs the steps of b whose conditions hold. The caller holds mu.
+func (s *Server) runBatch(st *stream, b *batch) (stepResults, stepErrors []interface{}) {
+	stepResults = make([]interface{}, len(b.Steps))
+	stepErrors = make([]interface{}, len(b.Steps))
+	ran := make([]bool, len(b.Steps))
+	failed := make([]bool, len(b.Steps))
+	for j, step := range b.Steps {
+		if step.Condition != nil && !evaluate(*step.Condition, st, ran, failed) {
+			continue
+		}
+		ran[j] = true
+		result, err := s.execute(st, step.Stmt)
+		if err != nil {
+			failed[j] = true
+			stepErrors[j] = err
+			continue
+		}
+		stepResults[j] = result
+	}
+	return stepResults, stepErrors
+}
+
+func okResult(kind string, result interface{}) map[string]interface{} {
+	return map[string]interface{}{"type": "ok", "response": map[string]interface{}{"type": kind, "result": result}}
+}
+
+func errorResult(message, code string) map[string]interface{} {
+	return map[string]interface{}{"type": "error", "error": errorBody{Message: message, Code: code}}
+}
+
+func evaluate(c condition, st *stream, ran, failed []bool) bool {
+	switch c.Type {
+	case "ok":
+		return c.Step < len(ran) && ran[c.Step] && !failed[c.Step]
+	case "error":
+		return c.Step < len(ran) && failed[c.Step]
+	case "not":
+		return c.Cond != nil && !evaluate(*c.Cond, st, ran, failed)
+	case "and":
+		for _, cond := range c.Conds {
+			if !evaluate(cond, st, ran, failed) {
+				return false
+			}
+		}
+		return true
+	case "or":
+		for _, cond := range c.Conds {
+			if evaluate(cond, st, ran, failed) {
+				return true
+			}
+		}
+		return false
+	case "is_autocommit":
+		return !st.inTx
+	}
+	return false
+}
+
+// execute runs a statement with its handler, or handles the transaction
+// statements itself.
+func (s *Server) execute(st *stream, in stmt) (map[string]interface{}, *errorBody) {
+	decoded := Stmt{SQL: in.SQL}
+	for _, arg := range in.Args {
+		v, err := decodeValue(arg)
+		if err != nil {
+			return nil, &errorBody{Message: err.Error(), Code: "ARGS_INVALID"}
+		}
+		decoded.Args = append(decoded.Args, v)
+	}
+	for _, arg := range in.NamedArgs {
+		v, err := decodeValue(arg.Value)
+		if err != nil {
+			return nil, &errorBody{Message: err.Error(), Code: "ARGS_INVALID"}
+		}
+		if decoded.NamedArgs == nil {
+			decoded.NamedArgs = map[string]interface{}{}
+		}
+		decoded.NamedArgs[arg.Name] = v
+	}
+	s.executed = append(s.executed, decoded)
+
+	sql := normalize(in.SQL)
+	handler, ok := s.handlers[sql]
+	if !ok {
+		keyword := strings.ToUpper(strings.SplitN(sql, " ", 2)[0])
+		switch keyword {
+		case "BEGIN":
+			if st.inTx {
+				return nil, &errorBody{Message: "cannot start a transaction within a transaction", Code: "SQLITE_ERROR"}
+			}
+			st.inTx = true
+			return encodeResult(&Result{}), nil
+		case "COMMIT", "END", "ROLLBACK":
+			if !st.inTx {
+				return nil, &errorBody{Message: "cannot " + strings.ToLower(keyword) + " - no transaction is active", Code: "SQLITE_ERROR"}
+			}
+			st.inTx = false
+			return encodeResult(&Result{}), nil
+		}
+		return nil, &errorBody{Message: fmt.Sprintf("fakehrana: no handler for %q", sql), Code: "SQLITE_ERROR"}
+	}
+	result, err := handler(decoded)
+	if err != nil {
+		if e, ok := err.(*Error); ok {
+			return nil, &errorBody{Message: e.Message, Code: e.Code}
+		}
+		return nil, &errorBody{Message: err.Error(), Code: "SQLITE_ERROR"}
+	}
+	if result == nil {
+		result = &Result{}
+	}
+	if in.WantRows != nil && !*in.WantRows {
+		withoutRows := *result
+		withoutRows.Rows = nil
+		result = &withoutRows
+	}
+	return encodeResult(result), nil
+}
+
+func encodeResult(r *Result) map[string]interface{} {
+	cols := make([]map[string]interface{}, len(r.Columns))
+	for i, name := range r.Columns {
+		cols[i] = map[string]interface{}{"name": name, "decltype": nil}
+	}
+	rows := make([][]value, len(r.Rows))
+	for i, row := range r.Rows {
+		rows[i] = make([]value, len(row))
+		for j, v := range row {
+			rows[i][j] = encodeValue(v)
+		}
+	}
+	var lastInsertRowID interface{}
+	if r.LastInsertRowID != 0 {
+		lastInsertRowID = strconv.FormatInt(r.LastInsertRowID, 10)
+	}
+	return map[string]interface{}{
+		"cols":               cols,
+		"rows":               rows,
+		"affected_row_count": r.AffectedRowCount,
+		"last_insert_rowid":  lastInsertRowID,
+		"rows_read":          len(r.Rows),
+		"rows_written":       r.AffectedRowCount,
+	}
+}
+
+func encodeValue(v interface{}) value {
+	switch v := v.(type) {
+	case nil:
+		return value{Type: "null"}
+	case int:
+		return value{Type: "integer", Value: strconv.Itoa(v)}
+	case int64:
+		return value{Type: "integer", Value: strconv.FormatInt(v, 10)}
+	case float64:
+		return value{Type: "float", Value: v}
+	case string:
+		return value{Type: "text", Value: v}
+	case []byte:
+		return value{Type: "blob", Base64: base64.StdEncoding.EncodeToString(v)}
+	}
+	panic(fmt.Sprintf("fakehrana: unsupported result value %T", v))
+}
+
+func decodeValue(v value) (interface{}, error) {
+	switch v.Type {
+	case "null":
+		return nil, nil
+	case "integer":
+		s, _ := v.Value.(string)
+		return strconv.ParseInt(s, 10, 64)
+	case "float":
+		f, ok := v.Value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("float value %v is not a number", v.Value)
+		}
+		return f, nil
+	case "text":
+		s, ok := v.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("text value %v is not a string", v.Value)
+		}
+		return s, nil
+	case "blob":
+		return base64.StdEncoding.DecodeString(v.Base64)
+	}
+	return nil, fmt.Errorf("unknown value type %q", v.Type)
+}
+
+func newBaton() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("fakehrana: generating baton: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -1,31 +1,48 @@
 package turso
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 )
 
 type Locations struct {
 	client *client
 }
 
-type locations struct {
-	Locations map[string]string
+// LocationsService lists the locations databases can be placed in.
+type LocationsService interface {
+	List() (*LocationList, error)
+	ListContext(ctx context.Context) (*LocationList, error)
+	Closest() (*Region, error)
+	ClosestContext(ctx context.Context) (*Region, error)
 }
 
-type region struct {
+var _ LocationsService = (*Locations)(nil)
+
+type LocationList struct {
+	Locations map[string]string `json:"locations"`
+}
+
+type Region struct {
 	Server string `json:"server"`
 	Client string `json:"client"`
 }
 
-func (loc *Locations) List() (*locations, error) {
-	endpoint := fmt.Sprintf("%s/v1/locations", tursoBaseURL)
-	resp, err := loc.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+func (loc *Locations) List() (*LocationList, error) {
+	return loc.ListContext(context.Background())
+}
+
+func (loc *Locations) ListContext(ctx context.Context) (*LocationList, error) {
+	endpoint := loc.client.endpoint("/v1/locations")
+	resp, err := loc.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
-	var locations = locations{}
+	var locations = LocationList{}
 	if err := json.NewDecoder(resp.Body).Decode(&locations); err != nil {
 		return nil, err
 	}
@@ -33,16 +50,33 @@ func (loc *Locations) List() (*locations, error) {
 	return &locations, nil
 }
 
-func (loc *Locations) Closest() (*locations, error) {
-	endpoint := fmt.Sprintf("%s", tursoBaseURLRegion)
-	resp, err := loc.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+func (loc *Locations) Closest() (*Region, error) {
+	return loc.ClosestContext(context.Background())
+}
+
+func (loc *Locations) ClosestContext(ctx context.Context) (*Region, error) {
+	endpoint := loc.client.regionEndpoint()
+	resp, err := loc.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
-	var locations = locations{}
-	if err := json.NewDecoder(resp.Body).Decode(&locations); err != nil {
+	var region = Region{}
+	if err := json.NewDecoder(resp.Body).Decode(&region); err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	return &locations, nil
+	return &region, nil
+}
+
+// validate checks that code is one of the listed locations.
+func (l *LocationList) validate(code string) error {
+	if _, ok := l.Locations[code]; ok {
+		return nil
+	}
+	codes := make([]string, 0, len(l.Locations))
+	for known := range l.Locations {
+		codes = append(codes, known)
+	}
+	sort.Strings(codes)
+	return fmt.Errorf("unknown location %q, valid locations are: %s", code, strings.Join(codes, ", "))
 }
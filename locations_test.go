@@ -23,11 +23,11 @@ func TestClosestLocation(t *testing.T) {
 	if err != nil || client == nil {
 		t.Error(err)
 	}
-	locations, err := client.Locations.Closest()
+	region, err := client.Locations.Closest()
 	if err != nil {
 		t.Error(err)
 	}
-	if locations == nil && len(locations.Locations) == 0 {
-		t.Error("locations should not be nil")
+	if region == nil && region.Server == "" {
+		t.Error("region should not be nil")
 	}
 }
@@ -0,0 +1,66 @@
+package turso
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(*client)
+
+// WithHTTPClient sets the http.Client used for every request. WithTransport
+// and WithTimeout are applied to a copy of it, whatever the order of the
+// options, and the client itself is not modified.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		if httpClient != nil {
+			c.api = httpClient
+		}
+	}
+}
+
+// WithTransport sets the http.RoundTripper of the underlying http.Client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *client) {
+		c.transport = transport
+	}
+}
+
+// WithTimeout sets the timeout of the underlying http.Client.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.timeout = &timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRegionURL sets the URL used to look up the closest region.
+func WithRegionURL(regionURL string) Option {
+	return func(c *client) {
+		if regionURL != "" {
+			c.regionURL = regionURL
+		}
+	}
+}
+
+// applyHTTPOptions applies the transport and timeout options to a copy of
+// the http.Client once every option has run.
+func (c *client) applyHTTPOptions() {
+	if c.transport == nil && c.timeout == nil {
+		return
+	}
+	api := *c.api
+	if c.transport != nil {
+		api.Transport = c.transport
+	}
+	if c.timeout != nil {
+		api.Timeout = *c.timeout
+	}
+	c.api = &api
+}
@@ -0,0 +1,86 @@
+package turso
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClientOptions(t *testing.T) {
+	httpClient := &http.Client{}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+	client, err := NewClient("", "token",
+		WithHTTPClient(httpClient),
+		WithTimeout(5*time.Second),
+		WithTransport(transport),
+		WithUserAgent("my-agent"),
+		WithRegionURL("http://region.local"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := client.client.api
+	if api.Timeout != 5*time.Second {
+		t.Errorf("timeout should be applied, got %v", api.Timeout)
+	}
+	if api.Transport == nil {
+		t.Error("transport should be applied")
+	}
+	if httpClient.Timeout != 0 || httpClient.Transport != nil {
+		t.Error("the provided http.Client should not be modified")
+	}
+	if client.client.regionURL != "http://region.local" {
+		t.Errorf("region url should be applied, got %s", client.client.regionURL)
+	}
+	if client.client.userAgent != "my-agent" {
+		t.Errorf("user agent should be applied, got %s", client.client.userAgent)
+	}
+}
+
+func TestClientOptionsOrder(t *testing.T) {
+	httpClient := &http.Client{}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+	client, err := NewClient("", "token",
+		WithTimeout(5*time.Second),
+		WithTransport(transport),
+		WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := client.client.api
+	if api.Timeout != 5*time.Second || api.Transport == nil {
+		t.Errorf("timeout and transport set before WithHTTPClient should be applied, got %v and %v", api.Timeout, api.Transport)
+	}
+	if httpClient.Timeout != 0 || httpClient.Transport != nil {
+		t.Error("the provided http.Client should not be modified")
+	}
+}
+
+func TestUserAgentHeader(t *testing.T) {
+	var userAgent string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		userAgent = req.Header.Get("User-Agent")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	client, err := NewClient("", "token", WithTransport(transport), WithUserAgent("my-agent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.client.tursoAPIrequest("https://api.turso.tech/v1/locations", http.MethodGet, nil); err != nil {
+		t.Fatal(err)
+	}
+	if userAgent != "my-agent" {
+		t.Errorf("expected user agent my-agent, got %s", userAgent)
+	}
+}
@@ -1,17 +1,106 @@
 package turso
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"time"
 )
 
 type Organizations struct {
 	client *client
 }
 
+// OrganizationsService manages organizations and their databases, instances, groups, members and invites.
+type OrganizationsService interface {
+	List() (*OrganisationList, error)
+	ListContext(ctx context.Context) (*OrganisationList, error)
+	Update(organizationSlug string, body map[string]string) error
+	UpdateContext(ctx context.Context, organizationSlug string, body map[string]string) error
+	Members(organizationSlug string) (*MemberList, error)
+	MembersContext(ctx context.Context, organizationSlug string) (*MemberList, error)
+	AddMembers(organizationSlug string, body map[string]string) error
+	AddMembersContext(ctx context.Context, organizationSlug string, body map[string]string) error
+	RemoveMembers(organizationSlug string, username string) error
+	RemoveMembersContext(ctx context.Context, organizationSlug string, username string) error
+	MintToken(organizationSlug, dbName string, opts MintTokenOptions) (*JWTToken, error)
+	MintTokenContext(ctx context.Context, organizationSlug, dbName string, opts MintTokenOptions) (*JWTToken, error)
+	MintGroupToken(orgSlug, groupName string, opts MintTokenOptions) (*JWTToken, error)
+	MintGroupTokenContext(ctx context.Context, orgSlug, groupName string, opts MintTokenOptions) (*JWTToken, error)
+	InvalidateTokens(organizationSlug, dbName string) error
+	InvalidateTokensContext(ctx context.Context, organizationSlug, dbName string) error
+	Databases(organizationSlug string) (*DatabaseList, error)
+	DatabasesContext(ctx context.Context, organizationSlug string) (*DatabaseList, error)
+	Database(orgSlug, dbName string) (*Database, error)
+	DatabaseContext(ctx context.Context, orgSlug, dbName string) (*Database, error)
+	CreateDatabase(orgSlug string, body CreateDatabaseRequest) (*Database, error)
+	CreateDatabaseContext(ctx context.Context, orgSlug string, body CreateDatabaseRequest) (*Database, error)
+	RetrieveDatabaseConfiguration(orgSlug, dbName string) (*DatabaseConfiguration, error)
+	RetrieveDatabaseConfigurationContext(ctx context.Context, orgSlug, dbName string) (*DatabaseConfiguration, error)
+	UpdateDatabaseConfiguration(orgSlug, dbName string, body map[string]string) (*DatabaseConfiguration, error)
+	UpdateDatabaseConfigurationContext(ctx context.Context, orgSlug, dbName string, body map[string]string) (*DatabaseConfiguration, error)
+	DeleteDatabase(orgSlug, dbName string) error
+	DeleteDatabaseContext(ctx context.Context, orgSlug, dbName string) error
+	UpdateDatabasesInGroup(orgSlug, groupName string) error
+	UpdateDatabasesInGroupContext(ctx context.Context, orgSlug, groupName string) error
+	UpdateAllInstances(orgSlug, dbName string) error
+	UpdateAllInstancesContext(ctx context.Context, orgSlug, dbName string) error
+	DBUsage(orgSlug, dbName string) (*DBMonthlyUsage, error)
+	DBUsageContext(ctx context.Context, orgSlug, dbName string) (*DBMonthlyUsage, error)
+	Instances(orgSlug, dbName string) (*InstanceList, error)
+	InstancesContext(ctx context.Context, orgSlug, dbName string) (*InstanceList, error)
+	Instance(orgSlug, dbName, instanceName string) (*Instance, error)
+	InstanceContext(ctx context.Context, orgSlug, dbName, instanceName string) (*Instance, error)
+	CreateInstance(orgSlug, dbName string, body map[string]string) (*Instance, error)
+	CreateInstanceContext(ctx context.Context, orgSlug, dbName string, body map[string]string) (*Instance, error)
+	DeleteInstance(orgSlug, dbName, instanceName string) error
+	DeleteInstanceContext(ctx context.Context, orgSlug, dbName, instanceName string) error
+	ListGroups(orgSlug string) (*GroupList, error)
+	ListGroupsContext(ctx context.Context, orgSlug string) (*GroupList, error)
+	Group(orgSlug, groupName string) (*OrganizationGroup, error)
+	GroupContext(ctx context.Context, orgSlug, groupName string) (*OrganizationGroup, error)
+	CreateGroup(orgSlug string, body CreateGroupRequest) (*OrganizationGroup, error)
+	CreateGroupContext(ctx context.Context, orgSlug string, body CreateGroupRequest) (*OrganizationGroup, error)
+	DeleteGroup(orgSlug, groupName string) error
+	DeleteGroupContext(ctx context.Context, orgSlug, groupName string) error
+	AddLocationToGroup(orgSlug, groupName, location string) (*OrganizationGroup, error)
+	AddLocationToGroupContext(ctx context.Context, orgSlug, groupName, location string) (*OrganizationGroup, error)
+	RemoveLocationFromGroup(orgSlug, groupName, location string) (*OrganizationGroup, error)
+	RemoveLocationFromGroupContext(ctx context.Context, orgSlug, groupName, location string) (*OrganizationGroup, error)
+	UploadDumpFile(orgSlug string, file io.Reader) error
+	UploadDumpFileContext(ctx context.Context, orgSlug string, file io.Reader) error
+	InvalidateAllDBTokens(orgSlug, dbName string) error
+	InvalidateAllDBTokensContext(ctx context.Context, orgSlug, dbName string) error
+	RotateGroupTokens(orgSlug, groupName string) error
+	RotateGroupTokensContext(ctx context.Context, orgSlug, groupName string) error
+	// Deprecated: Use RotateGroupTokens.
+	InvalidateAllGroupTokens(orgSlug, groupName, token string) error
+	// Deprecated: Use RotateGroupTokensContext.
+	InvalidateAllGroupTokensContext(ctx context.Context, orgSlug, groupName, token string) error
+	ListInvites(orgSlug string) (*OrganizationInvites, error)
+	ListInvitesContext(ctx context.Context, orgSlug string) (*OrganizationInvites, error)
+	CreateInvite(orgSlug string, body map[string]string) (*OrganizationInvite, error)
+	CreateInviteContext(ctx context.Context, orgSlug string, body map[string]string) (*OrganizationInvite, error)
+	TransferOrganisation(orgSlug, groupName, ToOrgSlug string) (*OrganizationGroup, error)
+	TransferOrganisationContext(ctx context.Context, orgSlug, groupName, ToOrgSlug string) (*OrganizationGroup, error)
+	DatabaseStats(orgSlug, dbName string) (*DatabaseStats, error)
+	DatabaseStatsContext(ctx context.Context, orgSlug, dbName string) (*DatabaseStats, error)
+	ListPlans(orgSlug string) (*Plan, error)
+	ListPlansContext(ctx context.Context, orgSlug string) (*Plan, error)
+	CurrentSubscription(orgSlug string) (*Subscription, error)
+	CurrentSubscriptionContext(ctx context.Context, orgSlug string) (*Subscription, error)
+	ListInvoices(orgSlug string) (*Invoices, error)
+	ListInvoicesContext(ctx context.Context, orgSlug string) (*Invoices, error)
+	OrganisationUsage(orgSlug string) (*OrganizationUsage, error)
+	OrganisationUsageContext(ctx context.Context, orgSlug string) (*OrganizationUsage, error)
+}
+
+var _ OrganizationsService = (*Organizations)(nil)
+
 type Organization struct {
 	Name          string `json:"name"`
 	Slug          string `json:"slug"`
@@ -74,6 +163,26 @@ type OrganizationGroup struct {
 	Locations []string `json:"locations"`
 }
 
+// CreateGroupRequest is the body of Organizations.CreateGroup. Location is
+// the code of the primary location of the group, and Extensions can be set
+// to "all" to enable every extension.
+type CreateGroupRequest struct {
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	Extensions string `json:"extensions,omitempty"`
+	Version    string `json:"version,omitempty"`
+}
+
+func (r CreateGroupRequest) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("group name is required")
+	}
+	if r.Location == "" {
+		return fmt.Errorf("location is required")
+	}
+	return nil
+}
+
 type OrganizationInvite struct {
 	Accepted       bool         `json:"Accepted"`
 	CreatedAt      string       `json:"CreatedAt"`
@@ -87,6 +196,12 @@ type OrganizationInvite struct {
 	Token          string       `json:"Token"`
 }
 
+// inviteEnvelope is the response of the create invite endpoint, which
+// wraps the invite in an "invited" object.
+type inviteEnvelope struct {
+	Invited OrganizationInvite `json:"invited"`
+}
+
 type OrganizationInvites struct {
 	Invites []OrganizationInvite `json:"invites"`
 }
@@ -99,43 +214,145 @@ type Database struct {
 	DbId            string   `json:"dbId"`
 	Regions         []string `json:"regions"`
 	PrimaryRegion   string   `json:"primaryRegion"`
+	Type            string   `json:"type"`
+	Version         string   `json:"version"`
+	Group           string   `json:"group"`
+	IsSchema        bool     `json:"is_schema"`
+	Schema          string   `json:"schema"`
+	Archived        bool     `json:"archived"`
+	Sleeping        bool     `json:"sleeping"`
+	AllowAttach     bool     `json:"allow_attach"`
+	BlockReads      bool     `json:"block_reads"`
+	BlockWrites     bool     `json:"block_writes"`
+}
+
+// CreateDatabaseRequest is the body of Organizations.CreateDatabase.
+type CreateDatabaseRequest struct {
+	Name      string        `json:"name"`
+	Group     string        `json:"group"`
+	Seed      *DatabaseSeed `json:"seed,omitempty"`
+	SizeLimit string        `json:"size_limit,omitempty"`
+	IsSchema  bool          `json:"is_schema,omitempty"`
+	Schema    string        `json:"schema,omitempty"`
+}
+
+const (
+	SeedTypeDatabase = "database"
+	SeedTypeDump     = "dump"
+)
+
+// DatabaseSeed populates a new database from another database, optionally
+// at a point in time, or from a dump file URL.
+type DatabaseSeed struct {
+	Type      string     `json:"type"`
+	Name      string     `json:"name,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+func SeedFromDatabase(name string) *DatabaseSeed {
+	return &DatabaseSeed{Type: SeedTypeDatabase, Name: name}
+}
+
+func SeedFromPointInTime(name string, timestamp time.Time) *DatabaseSeed {
+	timestamp = timestamp.UTC()
+	return &DatabaseSeed{Type: SeedTypeDatabase, Name: name, Timestamp: &timestamp}
 }
 
-type topQueries struct {
+func SeedFromDump(dumpURL string) *DatabaseSeed {
+	return &DatabaseSeed{Type: SeedTypeDump, URL: dumpURL}
+}
+
+var databaseNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+var sizeLimitPattern = regexp.MustCompile(`^[0-9]+(b|kb|mb|gb)$`)
+
+// Validate checks the request before it is sent to the API.
+func (r CreateDatabaseRequest) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("database name is required")
+	}
+	if len(r.Name) > 64 || !databaseNamePattern.MatchString(r.Name) {
+		return fmt.Errorf("database name %q must be at most 64 lowercase letters, numbers or dashes", r.Name)
+	}
+	if r.Group == "" {
+		return fmt.Errorf("group name is required")
+	}
+	if r.SizeLimit != "" && !sizeLimitPattern.MatchString(r.SizeLimit) {
+		return fmt.Errorf("size limit %q must be a number followed by b, kb, mb or gb", r.SizeLimit)
+	}
+	if r.IsSchema && r.Schema != "" {
+		return fmt.Errorf("a schema database cannot use another schema")
+	}
+	if r.Seed != nil {
+		return r.Seed.Validate()
+	}
+	return nil
+}
+
+func (s DatabaseSeed) Validate() error {
+	switch s.Type {
+	case SeedTypeDatabase:
+		if s.Name == "" {
+			return fmt.Errorf("seed database name is required")
+		}
+		if s.URL != "" {
+			return fmt.Errorf("seed url is only allowed with a dump seed")
+		}
+		if s.Timestamp != nil && s.Timestamp.After(time.Now()) {
+			return fmt.Errorf("seed timestamp cannot be in the future")
+		}
+	case SeedTypeDump:
+		if s.URL == "" {
+			return fmt.Errorf("seed dump url is required")
+		}
+		dumpURL, err := url.Parse(s.URL)
+		if err != nil || (dumpURL.Scheme != "http" && dumpURL.Scheme != "https") || dumpURL.Host == "" {
+			return fmt.Errorf("seed dump url %q must be an http or https url", s.URL)
+		}
+		if s.Name != "" || s.Timestamp != nil {
+			return fmt.Errorf("seed name and timestamp are only allowed with a database seed")
+		}
+	default:
+		return fmt.Errorf("seed type %q must be %q or %q", s.Type, SeedTypeDatabase, SeedTypeDump)
+	}
+	return nil
+}
+
+type TopQuery struct {
 	Query       string `json:"query"`
 	RowsRead    int    `json:"rows_read"`
 	RowsWritten int    `json:"rows_written"`
 }
 
 type DatabaseStats struct {
-	TopQueries []topQueries `json:"top_queries"`
+	TopQueries []TopQuery `json:"top_queries"`
 }
 
-type usage struct {
+type Usage struct {
 	RowsRead     int `json:"rows_read"`
 	RowsWritten  int `json:"rows_written"`
 	StorageBytes int `json:"storage_bytes"`
 }
 
-type instanceUsage struct {
+type InstanceUsage struct {
 	UUID  string `json:"uuid"`
-	Usage usage  `json:"usage"`
+	Usage Usage  `json:"usage"`
 }
 
-type dbUsage struct {
-	UUID      string        `json:"uuid"`
-	Instances instanceUsage `json:"instances"`
-	Total     usage         `json:"total"`
+type DatabaseUsage struct {
+	UUID      string          `json:"uuid"`
+	Instances []InstanceUsage `json:"instances"`
+	Total     Usage           `json:"total"`
 }
 
 type DBMonthlyUsage struct {
-	Database dbUsage `json:"database"`
+	Database DatabaseUsage `json:"database"`
 }
 
 type OrgDBUsage struct {
-	UUID      string        `json:"uuid"`
-	Instances instanceUsage `json:"instances"`
-	Total     usage         `json:"total"`
+	UUID      string          `json:"uuid"`
+	Instances []InstanceUsage `json:"instances"`
+	Total     Usage           `json:"total"`
 }
 
 type OrgUsage struct {
@@ -164,56 +381,58 @@ type Instance struct {
 	Name     string `json:"name"`
 }
 
-type organizationMembersList struct {
+type MemberList struct {
 	Members []OrganizationMembers `json:"members"`
 }
 
-type organizationGroup struct {
+type groupEnvelope struct {
 	Group OrganizationGroup `json:"group"`
 }
 
-type organizationGroupList struct {
+type GroupList struct {
 	Groups []OrganizationGroup `json:"groups"`
 }
 
-type organizationDatabaseList struct {
+type DatabaseList struct {
 	Databases []Database `json:"databases"`
 }
 
-type organizationDatabase struct {
-	Database struct {
-		Database
-	} `json:"database"`
+type databaseEnvelope struct {
+	Database Database `json:"database"`
 }
 
-type organizationDatabaseConfig struct {
+// DatabaseConfiguration is the configuration of a database. The API names
+// the blocking flags block_reads and block_writes.
+type DatabaseConfiguration struct {
 	AllowAttach   bool   `json:"allow_attach"`
 	SizeLimit     string `json:"size_limit"`
-	BlockedReads  bool   `json:"blocked_reads"`
-	BlockedWrites bool   `json:"blocked_writes"`
+	BlockedReads  bool   `json:"block_reads"`
+	BlockedWrites bool   `json:"block_writes"`
 }
 
-type DatabaseConfiguration struct {
-	organizationDatabaseConfig
-}
-
-type databaseInstances struct {
+type InstanceList struct {
 	Instances []Instance `json:"instances"`
 }
 
-type databaseInstance struct {
-	Instance struct {
-		Instance
-	} `json:"instance"`
+type instanceEnvelope struct {
+	Instance Instance `json:"instance"`
 }
 
-type jwtToken struct {
+type JWTToken struct {
 	JWT string `json:"jwt"`
+	// Claims are decoded from JWT; nil when decoding failed with ClaimsErr,
+	// which leaves the token itself usable.
+	Claims    *TokenClaims `json:"-"`
+	ClaimsErr error        `json:"-"`
 }
 
 func (org *Organizations) List() (*OrganisationList, error) {
-	endpoint := fmt.Sprintf("%s/v1/organizations", tursoBaseURL)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	return org.ListContext(context.Background())
+}
+
+func (org *Organizations) ListContext(ctx context.Context) (*OrganisationList, error) {
+	endpoint := org.client.endpoint("/v1/organizations")
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -227,13 +446,15 @@ func (org *Organizations) List() (*OrganisationList, error) {
 }
 
 func (org *Organizations) Update(organizationSlug string, body map[string]string) error {
+	return org.UpdateContext(context.Background(), organizationSlug, body)
+}
+
+func (org *Organizations) UpdateContext(ctx context.Context, organizationSlug string, body map[string]string) error {
 	if organizationSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s", tursoBaseURL, organizationSlug)
-	b := new(bytes.Buffer)
-	json.NewEncoder(b).Encode(body)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPut, b)
+	endpoint := org.client.endpoint("/v1/organizations/%s", organizationSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPut, body)
 	if err != nil {
 		return err
 	}
@@ -241,16 +462,20 @@ func (org *Organizations) Update(organizationSlug string, body map[string]string
 	return nil
 }
 
-func (org *Organizations) Members(organizationSlug string) (*organizationMembersList, error) {
+func (org *Organizations) Members(organizationSlug string) (*MemberList, error) {
+	return org.MembersContext(context.Background(), organizationSlug)
+}
+
+func (org *Organizations) MembersContext(ctx context.Context, organizationSlug string) (*MemberList, error) {
 	if organizationSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/members", tursoBaseURL, organizationSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/members", organizationSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
-	var members = organizationMembersList{}
+	var members = MemberList{}
 	err = json.NewDecoder(resp.Body).Decode(&members)
 	if err != nil {
 		return nil, err
@@ -260,13 +485,15 @@ func (org *Organizations) Members(organizationSlug string) (*organizationMembers
 }
 
 func (org *Organizations) AddMembers(organizationSlug string, body map[string]string) error {
+	return org.AddMembersContext(context.Background(), organizationSlug, body)
+}
+
+func (org *Organizations) AddMembersContext(ctx context.Context, organizationSlug string, body map[string]string) error {
 	if organizationSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/members", tursoBaseURL, organizationSlug)
-	b := new(bytes.Buffer)
-	json.NewEncoder(b).Encode(body)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPost, b)
+	endpoint := org.client.endpoint("/v1/organizations/%s/members", organizationSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPost, body)
 	if err != nil {
 		return err
 	}
@@ -275,11 +502,15 @@ func (org *Organizations) AddMembers(organizationSlug string, body map[string]st
 }
 
 func (org *Organizations) RemoveMembers(organizationSlug string, username string) error {
+	return org.RemoveMembersContext(context.Background(), organizationSlug, username)
+}
+
+func (org *Organizations) RemoveMembersContext(ctx context.Context, organizationSlug string, username string) error {
 	if organizationSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/members/%s", tursoBaseURL, organizationSlug, username)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodDelete, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/members/%s", organizationSlug, username)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodDelete, nil)
 	if err != nil {
 		return err
 	}
@@ -287,36 +518,73 @@ func (org *Organizations) RemoveMembers(organizationSlug string, username string
 	return nil
 }
 
-func (org *Organizations) MintToken(organizationSlug, dbName, expiration, authorization string) (*jwtToken, error) {
+// MintToken mints a token for a database.
+func (org *Organizations) MintToken(organizationSlug, dbName string, opts MintTokenOptions) (*JWTToken, error) {
+	return org.MintTokenContext(context.Background(), organizationSlug, dbName, opts)
+}
+
+func (org *Organizations) MintTokenContext(ctx context.Context, organizationSlug, dbName string, opts MintTokenOptions) (*JWTToken, error) {
 	if organizationSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return nil, fmt.Errorf("database name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/auth/tokens", tursoBaseURL, organizationSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPost, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/auth/tokens", organizationSlug, dbName)
+	return org.mintToken(ctx, endpoint, opts)
+}
+
+// MintGroupToken mints a token valid for every database of a group.
+func (org *Organizations) MintGroupToken(orgSlug, groupName string, opts MintTokenOptions) (*JWTToken, error) {
+	return org.MintGroupTokenContext(context.Background(), orgSlug, groupName, opts)
+}
+
+func (org *Organizations) MintGroupTokenContext(ctx context.Context, orgSlug, groupName string, opts MintTokenOptions) (*JWTToken, error) {
+	if orgSlug == "" {
+		return nil, fmt.Errorf("organization slug is required")
+	}
+	if groupName == "" {
+		return nil, fmt.Errorf("group name is required")
+	}
+	endpoint := org.client.endpoint("/v1/organizations/%s/groups/%s/auth/tokens", orgSlug, groupName)
+	return org.mintToken(ctx, endpoint, opts)
+}
+
+func (org *Organizations) mintToken(ctx context.Context, endpoint string, opts MintTokenOptions) (*JWTToken, error) {
+	query, err := opts.query()
 	if err != nil {
 		return nil, err
 	}
-	var jwtToken = jwtToken{}
-	err = json.NewDecoder(resp.Body).Decode(&jwtToken)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint+"?"+query, http.MethodPost, opts.body())
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	return &jwtToken, nil
+	var token JWTToken
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	if claims, err := ParseTokenClaims(token.JWT); err != nil {
+		token.ClaimsErr = err
+	} else {
+		token.Claims = &claims
+	}
+	return &token, nil
 }
 
 func (org *Organizations) InvalidateTokens(organizationSlug, dbName string) error {
+	return org.InvalidateTokensContext(context.Background(), organizationSlug, dbName)
+}
+
+func (org *Organizations) InvalidateTokensContext(ctx context.Context, organizationSlug, dbName string) error {
 	if organizationSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return fmt.Errorf("database name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/auth/tokens", tursoBaseURL, organizationSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodDelete, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/auth/tokens", organizationSlug, dbName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodDelete, nil)
 	if err != nil {
 		return err
 	}
@@ -324,16 +592,20 @@ func (org *Organizations) InvalidateTokens(organizationSlug, dbName string) erro
 	return nil
 }
 
-func (org *Organizations) Databases(organizationSlug string) (*organizationDatabaseList, error) {
+func (org *Organizations) Databases(organizationSlug string) (*DatabaseList, error) {
+	return org.DatabasesContext(context.Background(), organizationSlug)
+}
+
+func (org *Organizations) DatabasesContext(ctx context.Context, organizationSlug string) (*DatabaseList, error) {
 	if organizationSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases", tursoBaseURL, organizationSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases", organizationSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
-	var database = organizationDatabaseList{}
+	var database = DatabaseList{}
 	err = json.NewDecoder(resp.Body).Decode(&database)
 	if err != nil {
 		return nil, err
@@ -342,50 +614,67 @@ func (org *Organizations) Databases(organizationSlug string) (*organizationDatab
 	return &database, nil
 }
 
-func (org *Organizations) Database(orgSlug, dbName string) (*organizationDatabase, error) {
+func (org *Organizations) Database(orgSlug, dbName string) (*Database, error) {
+	return org.DatabaseContext(context.Background(), orgSlug, dbName)
+}
+
+func (org *Organizations) DatabaseContext(ctx context.Context, orgSlug, dbName string) (*Database, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return nil, fmt.Errorf("database name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s", tursoBaseURL, orgSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s", orgSlug, dbName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
-	var database = organizationDatabase{}
-	json.NewDecoder(resp.Body).Decode(&database)
 	defer resp.Body.Close()
-	return &database, nil
+	var database = databaseEnvelope{}
+	if err := json.NewDecoder(resp.Body).Decode(&database); err != nil {
+		return nil, err
+	}
+	return &database.Database, nil
+}
+
+func (org *Organizations) CreateDatabase(orgSlug string, body CreateDatabaseRequest) (*Database, error) {
+	return org.CreateDatabaseContext(context.Background(), orgSlug, body)
 }
 
-func (org *Organizations) CreateDatabase(orgSlug string, body map[string]string) (*Database, error) {
+func (org *Organizations) CreateDatabaseContext(ctx context.Context, orgSlug string, body CreateDatabaseRequest) (*Database, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
-	b := new(bytes.Buffer)
-	json.NewEncoder(b).Encode(body)
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases", tursoBaseURL, orgSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPost, b)
+	if err := body.Validate(); err != nil {
+		return nil, err
+	}
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases", orgSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPost, body)
 	if err != nil {
 		return nil, err
 	}
-	var database = Database{}
-	json.NewDecoder(resp.Body).Decode(&database)
 	defer resp.Body.Close()
-	return &database, nil
+	var database = databaseEnvelope{}
+	if err := json.NewDecoder(resp.Body).Decode(&database); err != nil {
+		return nil, err
+	}
+	return &database.Database, nil
 }
 
 func (org *Organizations) RetrieveDatabaseConfiguration(orgSlug, dbName string) (*DatabaseConfiguration, error) {
+	return org.RetrieveDatabaseConfigurationContext(context.Background(), orgSlug, dbName)
+}
+
+func (org *Organizations) RetrieveDatabaseConfigurationContext(ctx context.Context, orgSlug, dbName string) (*DatabaseConfiguration, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return nil, fmt.Errorf("database name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/configuration", tursoBaseURL, orgSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/configuration", orgSlug, dbName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -396,14 +685,18 @@ func (org *Organizations) RetrieveDatabaseConfiguration(orgSlug, dbName string)
 }
 
 func (org *Organizations) UpdateDatabaseConfiguration(orgSlug, dbName string, body map[string]string) (*DatabaseConfiguration, error) {
+	return org.UpdateDatabaseConfigurationContext(context.Background(), orgSlug, dbName, body)
+}
+
+func (org *Organizations) UpdateDatabaseConfigurationContext(ctx context.Context, orgSlug, dbName string, body map[string]string) (*DatabaseConfiguration, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return nil, fmt.Errorf("database name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/configuration", tursoBaseURL, orgSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPatch, body)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/configuration", orgSlug, dbName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPatch, body)
 	if err != nil {
 		return nil, err
 	}
@@ -414,14 +707,18 @@ func (org *Organizations) UpdateDatabaseConfiguration(orgSlug, dbName string, bo
 }
 
 func (org *Organizations) DeleteDatabase(orgSlug, dbName string) error {
+	return org.DeleteDatabaseContext(context.Background(), orgSlug, dbName)
+}
+
+func (org *Organizations) DeleteDatabaseContext(ctx context.Context, orgSlug, dbName string) error {
 	if orgSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return fmt.Errorf("database name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s", tursoBaseURL, orgSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodDelete, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s", orgSlug, dbName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodDelete, nil)
 	if err != nil {
 		return err
 	}
@@ -430,14 +727,18 @@ func (org *Organizations) DeleteDatabase(orgSlug, dbName string) error {
 }
 
 func (org *Organizations) UpdateDatabasesInGroup(orgSlug, groupName string) error {
+	return org.UpdateDatabasesInGroupContext(context.Background(), orgSlug, groupName)
+}
+
+func (org *Organizations) UpdateDatabasesInGroupContext(ctx context.Context, orgSlug, groupName string) error {
 	if orgSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
 	if groupName == "" {
 		return fmt.Errorf("group name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/groups/%s/update", tursoBaseURL, orgSlug, groupName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPut, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/groups/%s/update", orgSlug, groupName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPut, nil)
 	if err != nil {
 		return err
 	}
@@ -446,14 +747,18 @@ func (org *Organizations) UpdateDatabasesInGroup(orgSlug, groupName string) erro
 }
 
 func (org *Organizations) UpdateAllInstances(orgSlug, dbName string) error {
+	return org.UpdateAllInstancesContext(context.Background(), orgSlug, dbName)
+}
+
+func (org *Organizations) UpdateAllInstancesContext(ctx context.Context, orgSlug, dbName string) error {
 	if orgSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return fmt.Errorf("database name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/update", tursoBaseURL, orgSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPut, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/update", orgSlug, dbName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPut, nil)
 	if err != nil {
 		return err
 	}
@@ -462,14 +767,18 @@ func (org *Organizations) UpdateAllInstances(orgSlug, dbName string) error {
 }
 
 func (org *Organizations) DBUsage(orgSlug, dbName string) (*DBMonthlyUsage, error) {
+	return org.DBUsageContext(context.Background(), orgSlug, dbName)
+}
+
+func (org *Organizations) DBUsageContext(ctx context.Context, orgSlug, dbName string) (*DBMonthlyUsage, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return nil, fmt.Errorf("database name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/usage", tursoBaseURL, orgSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/usage", orgSlug, dbName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -479,25 +788,33 @@ func (org *Organizations) DBUsage(orgSlug, dbName string) (*DBMonthlyUsage, erro
 	return &usage, nil
 }
 
-func (org *Organizations) Instances(orgSlug, dbName string) (*databaseInstances, error) {
+func (org *Organizations) Instances(orgSlug, dbName string) (*InstanceList, error) {
+	return org.InstancesContext(context.Background(), orgSlug, dbName)
+}
+
+func (org *Organizations) InstancesContext(ctx context.Context, orgSlug, dbName string) (*InstanceList, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return nil, fmt.Errorf("database name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/instances", tursoBaseURL, orgSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/instances", orgSlug, dbName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
-	var instances = databaseInstances{}
+	var instances = InstanceList{}
 	json.NewDecoder(resp.Body).Decode(&instances)
 	defer resp.Body.Close()
 	return &instances, nil
 }
 
-func (org *Organizations) Instance(orgSlug, dbName, instanceName string) (*databaseInstance, error) {
+func (org *Organizations) Instance(orgSlug, dbName, instanceName string) (*Instance, error) {
+	return org.InstanceContext(context.Background(), orgSlug, dbName, instanceName)
+}
+
+func (org *Organizations) InstanceContext(ctx context.Context, orgSlug, dbName, instanceName string) (*Instance, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
@@ -507,38 +824,48 @@ func (org *Organizations) Instance(orgSlug, dbName, instanceName string) (*datab
 	if instanceName == "" {
 		return nil, fmt.Errorf("instance name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/instances/%s", tursoBaseURL, orgSlug, dbName, instanceName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/instances/%s", orgSlug, dbName, instanceName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
-	var instance = databaseInstance{}
-	json.NewDecoder(resp.Body).Decode(&instance)
 	defer resp.Body.Close()
-	return &instance, nil
+	var instance = instanceEnvelope{}
+	if err := json.NewDecoder(resp.Body).Decode(&instance); err != nil {
+		return nil, err
+	}
+	return &instance.Instance, nil
 }
 
-func (org *Organizations) CreateInstance(orgSlug, dbName string, body map[string]string) (*databaseInstance, error) {
+func (org *Organizations) CreateInstance(orgSlug, dbName string, body map[string]string) (*Instance, error) {
+	return org.CreateInstanceContext(context.Background(), orgSlug, dbName, body)
+}
+
+func (org *Organizations) CreateInstanceContext(ctx context.Context, orgSlug, dbName string, body map[string]string) (*Instance, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return nil, fmt.Errorf("database name is required")
 	}
-	b := new(bytes.Buffer)
-	json.NewEncoder(b).Encode(body)
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/instances", tursoBaseURL, orgSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPost, b)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/instances", orgSlug, dbName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPost, body)
 	if err != nil {
 		return nil, err
 	}
-	var instance = databaseInstance{}
-	json.NewDecoder(resp.Body).Decode(&instance)
 	defer resp.Body.Close()
-	return &instance, nil
+	var instance = instanceEnvelope{}
+	if err := json.NewDecoder(resp.Body).Decode(&instance); err != nil {
+		return nil, err
+	}
+	return &instance.Instance, nil
 }
 
 func (org *Organizations) DeleteInstance(orgSlug, dbName, instanceName string) error {
+	return org.DeleteInstanceContext(context.Background(), orgSlug, dbName, instanceName)
+}
+
+func (org *Organizations) DeleteInstanceContext(ctx context.Context, orgSlug, dbName, instanceName string) error {
 	if orgSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
@@ -548,8 +875,8 @@ func (org *Organizations) DeleteInstance(orgSlug, dbName, instanceName string) e
 	if instanceName == "" {
 		return fmt.Errorf("instance name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/instances/%s", tursoBaseURL, orgSlug, dbName, instanceName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodDelete, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/instances/%s", orgSlug, dbName, instanceName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodDelete, nil)
 	if err != nil {
 		return err
 	}
@@ -557,65 +884,95 @@ func (org *Organizations) DeleteInstance(orgSlug, dbName, instanceName string) e
 	return nil
 }
 
-func (org *Organizations) ListGroups(orgSlug string) (*organizationGroupList, error) {
+func (org *Organizations) ListGroups(orgSlug string) (*GroupList, error) {
+	return org.ListGroupsContext(context.Background(), orgSlug)
+}
+
+func (org *Organizations) ListGroupsContext(ctx context.Context, orgSlug string) (*GroupList, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/groups", tursoBaseURL, orgSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/groups", orgSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
-	var groups = organizationGroupList{}
+	var groups = GroupList{}
 	json.NewDecoder(resp.Body).Decode(&groups)
 	defer resp.Body.Close()
 	return &groups, nil
 }
 
-func (org *Organizations) Group(orgSlug, groupName string) (*organizationGroup, error) {
+func (org *Organizations) Group(orgSlug, groupName string) (*OrganizationGroup, error) {
+	return org.GroupContext(context.Background(), orgSlug, groupName)
+}
+
+func (org *Organizations) GroupContext(ctx context.Context, orgSlug, groupName string) (*OrganizationGroup, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
 	if groupName == "" {
 		return nil, fmt.Errorf("group name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/groups/%s", tursoBaseURL, orgSlug, groupName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/groups/%s", orgSlug, groupName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
-	var group = organizationGroup{}
-	json.NewDecoder(resp.Body).Decode(&group)
 	defer resp.Body.Close()
-	return &group, nil
+	var group = groupEnvelope{}
+	if err := json.NewDecoder(resp.Body).Decode(&group); err != nil {
+		return nil, err
+	}
+	return &group.Group, nil
+}
+
+func (org *Organizations) CreateGroup(orgSlug string, body CreateGroupRequest) (*OrganizationGroup, error) {
+	return org.CreateGroupContext(context.Background(), orgSlug, body)
 }
 
-func (org *Organizations) CreateGroup(orgSlug string, body map[string]string) (*organizationGroup, error) {
+// CreateGroupContext validates the request, including its location against
+// the codes returned by Locations.List, before creating the group.
+func (org *Organizations) CreateGroupContext(ctx context.Context, orgSlug string, body CreateGroupRequest) (*OrganizationGroup, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
-	b := new(bytes.Buffer)
-	json.NewEncoder(b).Encode(body)
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/groups", tursoBaseURL, orgSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPost, b)
+	if err := body.Validate(); err != nil {
+		return nil, err
+	}
+	locations, err := (&Locations{client: org.client}).ListContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := locations.validate(body.Location); err != nil {
+		return nil, err
+	}
+	endpoint := org.client.endpoint("/v1/organizations/%s/groups", orgSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPost, body)
 	if err != nil {
 		return nil, err
 	}
-	var group = organizationGroup{}
-	json.NewDecoder(resp.Body).Decode(&group)
 	defer resp.Body.Close()
-	return &group, nil
+	var group = groupEnvelope{}
+	if err := json.NewDecoder(resp.Body).Decode(&group); err != nil {
+		return nil, err
+	}
+	return &group.Group, nil
 }
 
 func (org *Organizations) DeleteGroup(orgSlug, groupName string) error {
+	return org.DeleteGroupContext(context.Background(), orgSlug, groupName)
+}
+
+func (org *Organizations) DeleteGroupContext(ctx context.Context, orgSlug, groupName string) error {
 	if orgSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
 	if groupName == "" {
 		return fmt.Errorf("group name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/groups/%s", tursoBaseURL, orgSlug, groupName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodDelete, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/groups/%s", orgSlug, groupName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodDelete, nil)
 	if err != nil {
 		return err
 	}
@@ -623,7 +980,11 @@ func (org *Organizations) DeleteGroup(orgSlug, groupName string) error {
 	return nil
 }
 
-func (org *Organizations) AddLocationToGroup(orgSlug, groupName, location string) (*organizationGroup, error) {
+func (org *Organizations) AddLocationToGroup(orgSlug, groupName, location string) (*OrganizationGroup, error) {
+	return org.AddLocationToGroupContext(context.Background(), orgSlug, groupName, location)
+}
+
+func (org *Organizations) AddLocationToGroupContext(ctx context.Context, orgSlug, groupName, location string) (*OrganizationGroup, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
@@ -633,18 +994,24 @@ func (org *Organizations) AddLocationToGroup(orgSlug, groupName, location string
 	if location == "" {
 		return nil, fmt.Errorf("location is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/groups/%s/locations/%s", tursoBaseURL, orgSlug, groupName, location)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPost, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/groups/%s/locations/%s", orgSlug, groupName, location)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPost, nil)
 	if err != nil {
 		return nil, err
 	}
-	var group = organizationGroup{}
-	json.NewDecoder(resp.Body).Decode(&group)
 	defer resp.Body.Close()
-	return &group, nil
+	var group = groupEnvelope{}
+	if err := json.NewDecoder(resp.Body).Decode(&group); err != nil {
+		return nil, err
+	}
+	return &group.Group, nil
 }
 
-func (org *Organizations) RemoveLocationFromGroup(orgSlug, groupName, location string) (*organizationGroup, error) {
+func (org *Organizations) RemoveLocationFromGroup(orgSlug, groupName, location string) (*OrganizationGroup, error) {
+	return org.RemoveLocationFromGroupContext(context.Background(), orgSlug, groupName, location)
+}
+
+func (org *Organizations) RemoveLocationFromGroupContext(ctx context.Context, orgSlug, groupName, location string) (*OrganizationGroup, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
@@ -654,23 +1021,29 @@ func (org *Organizations) RemoveLocationFromGroup(orgSlug, groupName, location s
 	if location == "" {
 		return nil, fmt.Errorf("location is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/groups/%s/locations/%s", tursoBaseURL, orgSlug, groupName, location)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodDelete, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/groups/%s/locations/%s", orgSlug, groupName, location)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodDelete, nil)
 	if err != nil {
 		return nil, err
 	}
-	var group = organizationGroup{}
-	json.NewDecoder(resp.Body).Decode(&group)
 	defer resp.Body.Close()
-	return &group, nil
+	var group = groupEnvelope{}
+	if err := json.NewDecoder(resp.Body).Decode(&group); err != nil {
+		return nil, err
+	}
+	return &group.Group, nil
 }
 
 func (org *Organizations) UploadDumpFile(orgSlug string, file io.Reader) error {
+	return org.UploadDumpFileContext(context.Background(), orgSlug, file)
+}
+
+func (org *Organizations) UploadDumpFileContext(ctx context.Context, orgSlug string, file io.Reader) error {
 	if orgSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/dumps", tursoBaseURL, orgSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPost, file)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/dumps", orgSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPost, file)
 	if err != nil {
 		return err
 	}
@@ -679,14 +1052,18 @@ func (org *Organizations) UploadDumpFile(orgSlug string, file io.Reader) error {
 }
 
 func (org *Organizations) InvalidateAllDBTokens(orgSlug, dbName string) error {
+	return org.InvalidateAllDBTokensContext(context.Background(), orgSlug, dbName)
+}
+
+func (org *Organizations) InvalidateAllDBTokensContext(ctx context.Context, orgSlug, dbName string) error {
 	if orgSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return fmt.Errorf("database name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/auth/rotate", tursoBaseURL, orgSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPost, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/auth/rotate", orgSlug, dbName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPost, nil)
 	if err != nil {
 		return err
 	}
@@ -694,18 +1071,21 @@ func (org *Organizations) InvalidateAllDBTokens(orgSlug, dbName string) error {
 	return nil
 }
 
-func (org *Organizations) InvalidateAllGroupTokens(orgSlug, groupName, token string) error {
+// RotateGroupTokens invalidates every token minted for a group or its
+// databases.
+func (org *Organizations) RotateGroupTokens(orgSlug, groupName string) error {
+	return org.RotateGroupTokensContext(context.Background(), orgSlug, groupName)
+}
+
+func (org *Organizations) RotateGroupTokensContext(ctx context.Context, orgSlug, groupName string) error {
 	if orgSlug == "" {
 		return fmt.Errorf("organization slug is required")
 	}
 	if groupName == "" {
 		return fmt.Errorf("group name is required")
 	}
-	if token == "" {
-		return fmt.Errorf("token is required")
-	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/groups/%s/auth/rotate", tursoBaseURL, orgSlug, groupName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPost, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/groups/%s/auth/rotate", orgSlug, groupName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPost, nil)
 	if err != nil {
 		return err
 	}
@@ -713,12 +1093,32 @@ func (org *Organizations) InvalidateAllGroupTokens(orgSlug, groupName, token str
 	return nil
 }
 
+// InvalidateAllGroupTokens rotates the tokens of a group. The token is
+// ignored.
+//
+// Deprecated: Use RotateGroupTokens.
+func (org *Organizations) InvalidateAllGroupTokens(orgSlug, groupName, token string) error {
+	return org.RotateGroupTokens(orgSlug, groupName)
+}
+
+// InvalidateAllGroupTokensContext rotates the tokens of a group. The token
+// is ignored.
+//
+// Deprecated: Use RotateGroupTokensContext.
+func (org *Organizations) InvalidateAllGroupTokensContext(ctx context.Context, orgSlug, groupName, token string) error {
+	return org.RotateGroupTokensContext(ctx, orgSlug, groupName)
+}
+
 func (org *Organizations) ListInvites(orgSlug string) (*OrganizationInvites, error) {
+	return org.ListInvitesContext(context.Background(), orgSlug)
+}
+
+func (org *Organizations) ListInvitesContext(ctx context.Context, orgSlug string) (*OrganizationInvites, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/invites", tursoBaseURL, orgSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/invites", orgSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -729,23 +1129,31 @@ func (org *Organizations) ListInvites(orgSlug string) (*OrganizationInvites, err
 }
 
 func (org *Organizations) CreateInvite(orgSlug string, body map[string]string) (*OrganizationInvite, error) {
+	return org.CreateInviteContext(context.Background(), orgSlug, body)
+}
+
+func (org *Organizations) CreateInviteContext(ctx context.Context, orgSlug string, body map[string]string) (*OrganizationInvite, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
-	b := new(bytes.Buffer)
-	json.NewEncoder(b).Encode(body)
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/invites", tursoBaseURL, orgSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPost, b)
+	endpoint := org.client.endpoint("/v1/organizations/%s/invites", orgSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPost, body)
 	if err != nil {
 		return nil, err
 	}
-	var invite = OrganizationInvite{}
-	json.NewDecoder(resp.Body).Decode(&invite)
 	defer resp.Body.Close()
-	return &invite, nil
+	var invite = inviteEnvelope{}
+	if err := json.NewDecoder(resp.Body).Decode(&invite); err != nil {
+		return nil, err
+	}
+	return &invite.Invited, nil
+}
+
+func (org *Organizations) TransferOrganisation(orgSlug, groupName, ToOrgSlug string) (*OrganizationGroup, error) {
+	return org.TransferOrganisationContext(context.Background(), orgSlug, groupName, ToOrgSlug)
 }
 
-func (org *Organizations) TransferOrganisation(orgSlug, groupName, ToOrgSlug string) (*organizationGroup, error) {
+func (org *Organizations) TransferOrganisationContext(ctx context.Context, orgSlug, groupName, ToOrgSlug string) (*OrganizationGroup, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
@@ -755,26 +1163,34 @@ func (org *Organizations) TransferOrganisation(orgSlug, groupName, ToOrgSlug str
 	if ToOrgSlug == "" {
 		return nil, fmt.Errorf("the organization slug to be transfer to is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/groups/%s/transfer", tursoBaseURL, orgSlug, groupName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodPost, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/groups/%s/transfer", orgSlug, groupName)
+	// The destination organization is sent in the body, not the path.
+	body := map[string]string{"organization": ToOrgSlug}
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodPost, body)
 	if err != nil {
 		return nil, err
 	}
-	var transfer = organizationGroup{}
-	json.NewDecoder(resp.Body).Decode(&transfer)
 	defer resp.Body.Close()
-	return &transfer, nil
+	var transfer = groupEnvelope{}
+	if err := json.NewDecoder(resp.Body).Decode(&transfer); err != nil {
+		return nil, err
+	}
+	return &transfer.Group, nil
 }
 
 func (org *Organizations) DatabaseStats(orgSlug, dbName string) (*DatabaseStats, error) {
+	return org.DatabaseStatsContext(context.Background(), orgSlug, dbName)
+}
+
+func (org *Organizations) DatabaseStatsContext(ctx context.Context, orgSlug, dbName string) (*DatabaseStats, error) {
 	if orgSlug == "" {
 		return nil, fmt.Errorf("organization slug is required")
 	}
 	if dbName == "" {
 		return nil, fmt.Errorf("database name is required")
 	}
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/databases/%s/stats", tursoBaseURL, orgSlug, dbName)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	endpoint := org.client.endpoint("/v1/organizations/%s/databases/%s/stats", orgSlug, dbName)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -785,8 +1201,12 @@ func (org *Organizations) DatabaseStats(orgSlug, dbName string) (*DatabaseStats,
 }
 
 func (org *Organizations) ListPlans(orgSlug string) (*Plan, error) {
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/plans", tursoBaseURL, orgSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	return org.ListPlansContext(context.Background(), orgSlug)
+}
+
+func (org *Organizations) ListPlansContext(ctx context.Context, orgSlug string) (*Plan, error) {
+	endpoint := org.client.endpoint("/v1/organizations/%s/plans", orgSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -797,8 +1217,12 @@ func (org *Organizations) ListPlans(orgSlug string) (*Plan, error) {
 }
 
 func (org *Organizations) CurrentSubscription(orgSlug string) (*Subscription, error) {
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/subscriptions", tursoBaseURL, orgSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	return org.CurrentSubscriptionContext(context.Background(), orgSlug)
+}
+
+func (org *Organizations) CurrentSubscriptionContext(ctx context.Context, orgSlug string) (*Subscription, error) {
+	endpoint := org.client.endpoint("/v1/organizations/%s/subscriptions", orgSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -809,8 +1233,12 @@ func (org *Organizations) CurrentSubscription(orgSlug string) (*Subscription, er
 }
 
 func (org *Organizations) ListInvoices(orgSlug string) (*Invoices, error) {
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/invoices", tursoBaseURL, orgSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	return org.ListInvoicesContext(context.Background(), orgSlug)
+}
+
+func (org *Organizations) ListInvoicesContext(ctx context.Context, orgSlug string) (*Invoices, error) {
+	endpoint := org.client.endpoint("/v1/organizations/%s/invoices", orgSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -821,8 +1249,12 @@ func (org *Organizations) ListInvoices(orgSlug string) (*Invoices, error) {
 }
 
 func (org *Organizations) OrganisationUsage(orgSlug string) (*OrganizationUsage, error) {
-	endpoint := fmt.Sprintf("%s/v1/organizations/%s/usage", tursoBaseURL, orgSlug)
-	resp, err := org.client.tursoAPIrequest(endpoint, http.MethodGet, nil)
+	return org.OrganisationUsageContext(context.Background(), orgSlug)
+}
+
+func (org *Organizations) OrganisationUsageContext(ctx context.Context, orgSlug string) (*OrganizationUsage, error) {
+	endpoint := org.client.endpoint("/v1/organizations/%s/usage", orgSlug)
+	resp, err := org.client.tursoAPIrequestContext(ctx, endpoint, http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1,8 +1,13 @@
 package turso
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 var org_name string = os.Getenv("TURSO_ORG_NAME")
@@ -68,7 +73,7 @@ func TestOrganizationDatabase(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	if database == nil && database.Database.Name != db_name {
+	if database == nil && database.Name != db_name {
 		t.Error("databases should not be nil")
 	}
 	database, err = client.Organizations.Database("", db_name)
@@ -112,7 +117,7 @@ func TestOrganizationInstance(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	if instances == nil && instances.Instance.Name != instance_name {
+	if instances == nil && instances.Name != instance_name {
 		t.Error("instances should not be nil")
 	}
 	instances, err = client.Organizations.Instance("", db_name, instance_name)
@@ -200,7 +205,7 @@ func TestOrganizationGroup(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	if groups == nil && groups.Group.Name != group_name {
+	if groups == nil && groups.Name != group_name {
 		t.Error("groups should not be nil")
 	}
 	groups, err = client.Organizations.Group("", group_name)
@@ -212,3 +217,179 @@ func TestOrganizationGroup(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestCreateDatabase(t *testing.T) {
+	client, recorded := newRecordingClient(t, `{"database": {"DbId": "db-id", "Hostname": "new-db-org.turso.io", "Name": "new-db"}}`)
+	timestamp := time.Date(2023, 12, 20, 9, 46, 8, 0, time.UTC)
+	database, err := client.Organizations.CreateDatabase("org", CreateDatabaseRequest{
+		Name:      "new-db",
+		Group:     "default",
+		Seed:      SeedFromPointInTime("old-db", timestamp),
+		SizeLimit: "1gb",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if database.DbId != "db-id" || database.Hostname != "new-db-org.turso.io" || database.Name != "new-db" {
+		t.Errorf("unexpected database %+v", database)
+	}
+	expected := `{"name":"new-db","group":"default","seed":{"type":"database","name":"old-db","timestamp":"2023-12-20T09:46:08Z"},"size_limit":"1gb"}`
+	if recorded.body != expected {
+		t.Errorf("unexpected body %s", recorded.body)
+	}
+}
+
+func TestCreateDatabaseRequestValidate(t *testing.T) {
+	invalid := []CreateDatabaseRequest{
+		{Group: "default"},
+		{Name: "My_DB", Group: "default"},
+		{Name: "db"},
+		{Name: "db", Group: "default", SizeLimit: "lots"},
+		{Name: "db", Group: "default", IsSchema: true, Schema: "parent"},
+		{Name: "db", Group: "default", Seed: &DatabaseSeed{Type: "backup"}},
+		{Name: "db", Group: "default", Seed: SeedFromDatabase("")},
+		{Name: "db", Group: "default", Seed: SeedFromDump("ftp://dumps/db.sql")},
+		{Name: "db", Group: "default", Seed: SeedFromPointInTime("old", time.Now().Add(time.Hour))},
+	}
+	for _, request := range invalid {
+		if err := request.Validate(); err == nil {
+			t.Errorf("request should be invalid: %+v", request)
+		}
+	}
+	valid := CreateDatabaseRequest{Name: "db-1", Group: "default", Seed: SeedFromDump("https://example.com/db.sql")}
+	if err := valid.Validate(); err != nil {
+		t.Error(err)
+	}
+	client, _ := NewClient("http://127.0.0.1:0", "token")
+	if _, err := client.Organizations.CreateDatabase("org", CreateDatabaseRequest{}); err == nil || err.Error() != "database name is required" {
+		t.Errorf("CreateDatabase should validate the request, got %v", err)
+	}
+}
+
+func TestCreateGroup(t *testing.T) {
+	created := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/locations", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"locations": {"ams": "Amsterdam, Netherlands", "lhr": "London, United Kingdom"}}`))
+	})
+	mux.HandleFunc("/v1/organizations/org/groups", func(w http.ResponseWriter, r *http.Request) {
+		created = true
+		w.Write([]byte(`{"group": {"name": "eu", "primary": "ams", "locations": ["ams"], "uuid": "group-id"}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client, err := NewClient(server.URL, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Organizations.CreateGroup("org", CreateGroupRequest{Name: "eu", Location: "amz"})
+	if err == nil || !strings.Contains(err.Error(), `unknown location "amz"`) {
+		t.Errorf("expected an unknown location error, got %v", err)
+	}
+	if created {
+		t.Error("group should not be created with an unknown location")
+	}
+	group, err := client.Organizations.CreateGroup("org", CreateGroupRequest{Name: "eu", Location: "ams"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.Name != "eu" || group.Primary != "ams" || group.UUID != "group-id" {
+		t.Errorf("unexpected group %+v", group)
+	}
+}
+
+func TestResultsUnwrapEnvelopes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/organizations/org/databases/db", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"database": {"Name": "db", "DbId": "db-id", "Hostname": "db-org.turso.io", "group": "default"}}`))
+	})
+	mux.HandleFunc("/v1/organizations/org/databases/db/instances/ams", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"instance": {"name": "ams", "region": "ams", "type": "primary"}}`))
+	})
+	mux.HandleFunc("/v1/organizations/org/groups/default", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"group": {"name": "default", "primary": "ams"}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client, err := NewClient(server.URL, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var database *Database
+	database, err = client.Organizations.Database("org", "db")
+	if err != nil || database.DbId != "db-id" || database.Group != "default" {
+		t.Errorf("unexpected database %+v: %v", database, err)
+	}
+	var instance *Instance
+	instance, err = client.Organizations.Instance("org", "db", "ams")
+	if err != nil || instance.Type != "primary" {
+		t.Errorf("unexpected instance %+v: %v", instance, err)
+	}
+	var group *OrganizationGroup
+	group, err = client.Organizations.Group("org", "default")
+	if err != nil || group.Primary != "ams" {
+		t.Errorf("unexpected group %+v: %v", group, err)
+	}
+}
+
+// newFixtureClient returns a client whose requests are answered with the
+// recorded API response in testdata/api/name.
+func newFixtureClient(t *testing.T, name string) (*Client, *recordedRequest) {
+	t.Helper()
+	response, err := os.ReadFile(filepath.Join("testdata", "api", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newRecordingClient(t, string(response))
+}
+
+func TestCreateInviteRecordedResponse(t *testing.T) {
+	client, recorded := newFixtureClient(t, "create_invite.json")
+	invite, err := client.Organizations.CreateInvite("iku", map[string]string{"email": "iku@turso.tech", "role": "member"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invite.Email != "iku@turso.tech" || invite.Role != "member" || invite.ID != 1 || invite.Organization.Slug != "iku" {
+		t.Errorf("invite not unwrapped from its envelope: %+v", invite)
+	}
+	if recorded.method != http.MethodPost || recorded.body != `{"email":"iku@turso.tech","role":"member"}` {
+		t.Errorf("unexpected request %+v", recorded)
+	}
+}
+
+func TestDatabaseConfigurationRecordedResponse(t *testing.T) {
+	client, _ := newFixtureClient(t, "database_configuration.json")
+	config, err := client.Organizations.RetrieveDatabaseConfiguration("iku", "my-db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DatabaseConfiguration{AllowAttach: true, SizeLimit: "10000", BlockedReads: false, BlockedWrites: true}
+	if *config != want {
+		t.Errorf("got %+v, want %+v", *config, want)
+	}
+}
+
+func TestDatabaseRecordedResponse(t *testing.T) {
+	client, _ := newFixtureClient(t, "database.json")
+	db, err := client.Organizations.Database("iku", "my-db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Hostname != "my-db-iku.turso.io" || !db.BlockReads || db.BlockWrites || db.PrimaryRegion != "lhr" {
+		t.Errorf("unexpected database %+v", db)
+	}
+}
+
+func TestTransferGroupRecordedResponse(t *testing.T) {
+	client, recorded := newFixtureClient(t, "transfer_group.json")
+	group, err := client.Organizations.TransferOrganisation("iku", "default", "new-org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.Name != "default" || group.UUID != "0a28102d-6906-11ee-8553-eaa7715aeaf2" {
+		t.Errorf("unexpected group %+v", group)
+	}
+	if recorded.method != http.MethodPost || recorded.body != `{"organization":"new-org"}` {
+		t.Errorf("the destination organization should be sent in the body, got %+v", recorded)
+	}
+}
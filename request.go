@@ -0,0 +1,99 @@
+package turso
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// requestBody is an encoded request body that can be sent once per attempt.
+type requestBody struct {
+	payload     []byte
+	stream      io.Reader
+	start       int64
+	contentType string
+	length      int64
+}
+
+// encodeRequestBody encodes body for tursoAPIrequest. Strings and byte
+// slices are sent as JSON documents, io.Readers are streamed unchanged and
+// every other value is encoded as JSON.
+func encodeRequestBody(body interface{}) (*requestBody, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return newPayloadBody([]byte(b), "application/json"), nil
+	case []byte:
+		return newPayloadBody(b, "application/json"), nil
+	case *bytes.Buffer:
+		if b == nil {
+			return nil, nil
+		}
+		return newPayloadBody(b.Bytes(), "application/octet-stream"), nil
+	case io.Reader:
+		return newStreamBody(b)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("encoding request body: %w", err)
+	}
+	return newPayloadBody(payload, "application/json"), nil
+}
+
+func newPayloadBody(payload []byte, contentType string) *requestBody {
+	return &requestBody{
+		payload:     payload,
+		contentType: contentType,
+		length:      int64(len(payload)),
+	}
+}
+
+func newStreamBody(stream io.Reader) (*requestBody, error) {
+	body := &requestBody{
+		stream:      stream,
+		contentType: "application/octet-stream",
+		length:      -1,
+	}
+	if seeker, ok := stream.(io.Seeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request body: %w", err)
+		}
+		end, err := seeker.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request body: %w", err)
+		}
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("encoding request body: %w", err)
+		}
+		body.start = start
+		body.length = end - start
+	} else if sized, ok := stream.(interface{ Len() int }); ok {
+		body.length = int64(sized.Len())
+	}
+	return body, nil
+}
+
+// replayable reports whether the body can be sent more than once.
+func (b *requestBody) replayable() bool {
+	if b == nil || b.stream == nil {
+		return true
+	}
+	_, ok := b.stream.(io.Seeker)
+	return ok
+}
+
+// reader returns the body to send for the next attempt.
+func (b *requestBody) reader() (io.Reader, error) {
+	if b.stream == nil {
+		return bytes.NewReader(b.payload), nil
+	}
+	if seeker, ok := b.stream.(io.Seeker); ok {
+		if _, err := seeker.Seek(b.start, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	return b.stream, nil
+}
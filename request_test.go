@@ -0,0 +1,52 @@
+package turso
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRequestBodyMap(t *testing.T) {
+	client, recorded := newRecordingClient(t, `{}`)
+	_, err := client.Organizations.UpdateDatabaseConfiguration("org", "db", map[string]string{"size_limit": "1gb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded.body != `{"size_limit":"1gb"}` {
+		t.Errorf("unexpected body %s", recorded.body)
+	}
+	if recorded.contentType != "application/json" || recorded.contentLength != int64(len(recorded.body)) {
+		t.Errorf("unexpected headers %+v", recorded)
+	}
+	if err := client.Organizations.AddMembers("org", map[string]string{"username": "me"}); err != nil {
+		t.Fatal(err)
+	}
+	if recorded.body != `{"username":"me"}` {
+		t.Errorf("unexpected body %s", recorded.body)
+	}
+}
+
+func TestRequestBodyReader(t *testing.T) {
+	client, recorded := newRecordingClient(t, `{}`)
+	dump := "CREATE TABLE users (id INTEGER);"
+	if err := client.Organizations.UploadDumpFile("org", strings.NewReader(dump)); err != nil {
+		t.Fatal(err)
+	}
+	if recorded.body != dump || recorded.contentLength != int64(len(dump)) {
+		t.Errorf("reader should be streamed unchanged, got %+v", recorded)
+	}
+	if recorded.contentType != "application/octet-stream" {
+		t.Errorf("unexpected content type %s", recorded.contentType)
+	}
+}
+
+func TestRequestBodyEncodingError(t *testing.T) {
+	client, err := NewClient("http://127.0.0.1:0", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.client.tursoAPIrequest("http://127.0.0.1:0", http.MethodPost, map[string]interface{}{"bad": make(chan int)})
+	if err == nil || !strings.HasPrefix(err.Error(), "encoding request body") {
+		t.Errorf("expected an encoding error, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package turso
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. A request is retried
+// when the API answers with 429 or a 5xx status, or when the connection
+// fails, until MaxAttempts is reached.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry. It doubles with every
+	// attempt, with jitter, up to MaxBackoff. A Retry-After header asking
+	// for a longer wait than MaxBackoff ends the retries with the APIError.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// RetryNonIdempotent allows retrying POST and PATCH requests, which
+	// may be applied twice by the API.
+	RetryNonIdempotent bool
+	// OnRetry is called before waiting for the next attempt.
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a failed attempt that is about to be retried.
+type RetryEvent struct {
+	Method  string
+	Path    string
+	Attempt int
+	// StatusCode is 0 when the attempt failed without a response.
+	StatusCode int
+	Err        error
+	Wait       time.Duration
+}
+
+// DefaultRetryPolicy returns a policy retrying up to 4 attempts with a
+// backoff between 500ms and 30s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+// WithRetryPolicy sets the retry policy used for every request.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *client) {
+		c.retry = policy
+	}
+}
+
+func (p RetryPolicy) allowed(method string) bool {
+	if p.MaxAttempts < 2 {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return p.RetryNonIdempotent
+}
+
+func (p RetryPolicy) retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// waitAllowed reports whether the Retry-After header of resp, if any, asks
+// for a wait no longer than MaxBackoff.
+func (p RetryPolicy) waitAllowed(resp *http.Response) bool {
+	if resp == nil || p.MaxBackoff <= 0 {
+		return true
+	}
+	retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"))
+	return !ok || retryAfter <= p.MaxBackoff
+}
+
+// backoff returns the wait before the attempt following attempt, honoring
+// the Retry-After header of resp when there is one.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	wait := p.MinBackoff
+	for i := 1; i < attempt && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if wait > 0 {
+		wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+	}
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok && retryAfter > wait {
+			wait = retryAfter
+		}
+	}
+	return wait
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+package turso
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryOnServerError(t *testing.T) {
+	server, recorded := newRecordingServer(t, `{"databases": []}`)
+	recorded.failures, recorded.failStatus = 2, http.StatusServiceUnavailable
+	var events []RetryEvent
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+		OnRetry: func(event RetryEvent) {
+			events = append(events, event)
+		},
+	}
+	client, err := NewClient(server.URL, "token", WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Organizations.Databases("org"); err != nil {
+		t.Fatal(err)
+	}
+	if recorded.attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", recorded.attempts)
+	}
+	if len(events) != 2 || events[0].StatusCode != http.StatusServiceUnavailable || events[1].Attempt != 2 {
+		t.Errorf("unexpected retry events %+v", events)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server, recorded := newRecordingServer(t, `{"databases": []}`)
+	recorded.failures, recorded.failStatus = 5, http.StatusTooManyRequests
+	policy := RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}
+	client, err := NewClient(server.URL, "token", WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Organizations.Databases("org")
+	if !IsRateLimited(err) {
+		t.Errorf("expected a rate limit error, got %v", err)
+	}
+	if recorded.attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", recorded.attempts)
+	}
+}
+
+func TestRetrySkipsNonIdempotent(t *testing.T) {
+	server, recorded := newRecordingServer(t, `{"databases": []}`)
+	recorded.failures, recorded.failStatus = 1, http.StatusInternalServerError
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+	client, err := NewClient(server.URL, "token", WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.client.tursoAPIrequest(server.URL, http.MethodPost, `{}`); err == nil {
+		t.Error("POST should not be retried")
+	}
+	if recorded.attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", recorded.attempts)
+	}
+
+	policy.RetryNonIdempotent = true
+	client, _ = NewClient(server.URL, "token", WithRetryPolicy(policy))
+	recorded.attempts = 0
+	if _, err := client.client.tursoAPIrequest(server.URL, http.MethodPost, `{}`); err != nil {
+		t.Errorf("POST should be retried when opted in: %v", err)
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	server, recorded := newRecordingServer(t, `{"databases": []}`)
+	recorded.failures, recorded.failStatus = 5, http.StatusServiceUnavailable
+	policy := RetryPolicy{MaxAttempts: 5, MinBackoff: time.Hour, MaxBackoff: time.Hour}
+	client, err := NewClient(server.URL, "token", WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.Organizations.DatabasesContext(ctx, "org")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Minute}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	if wait := policy.backoff(1, resp); wait != 2*time.Second {
+		t.Errorf("expected Retry-After to be honored, got %v", wait)
+	}
+}
+
+func TestRetryAfterBeyondMaxBackoff(t *testing.T) {
+	server, recorded := newRecordingServer(t, `{"databases": []}`)
+	recorded.failures, recorded.failStatus, recorded.retryAfter = 1, http.StatusTooManyRequests, "86400"
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Minute}
+	client, err := NewClient(server.URL, "token", WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Organizations.Databases("org")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !IsRateLimited(err) {
+		t.Errorf("expected the rate limit APIError, got %v", err)
+	}
+	if recorded.attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", recorded.attempts)
+	}
+}
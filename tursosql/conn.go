@@ -0,0 +1,263 @@
+package tursosql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mr-destructive/turso-go/hrana"
+)
+
+// conn runs statements on its own, each in a separate Hrana stream, or in
+// the stream of its current transaction.
+type conn struct {
+	connector *Connector
+	tx        *hrana.Tx
+	// txClient is the client that began tx.
+	txClient *hrana.Client
+}
+
+var (
+	_ driver.Conn               = (*conn)(nil)
+	_ driver.ConnBeginTx        = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.Pinger             = (*conn)(nil)
+)
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext returns a statement that sends query each time it runs;
+// Hrana has no server-side prepared statements.
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+// Close rolls back the transaction left open on the connection, if any.
+func (c *conn) Close() error {
+	if c.tx == nil {
+		return nil
+	}
+	tx := c.tx
+	c.tx, c.txClient = nil, nil
+	if err := tx.Rollback(context.Background()); err != nil && !errors.Is(err, hrana.ErrTxDone) {
+		return err
+	}
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx begins a transaction. SQLite transactions are serializable, so
+// only the default and serializable isolation levels are accepted.
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, fmt.Errorf("tursosql: connection already has a transaction")
+	}
+	switch sql.IsolationLevel(opts.Isolation) {
+	case sql.LevelDefault, sql.LevelSerializable:
+	default:
+		return nil, fmt.Errorf("tursosql: unsupported isolation level %v", sql.IsolationLevel(opts.Isolation))
+	}
+	if opts.ReadOnly {
+		return nil, fmt.Errorf("tursosql: read-only transactions are not supported")
+	}
+	client, err := c.connector.hranaClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := client.Begin(ctx)
+	if err != nil {
+		return nil, c.connector.checkError(client, err)
+	}
+	c.tx, c.txClient = tx, client
+	return &transaction{conn: c}, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res, err := c.execute(ctx, query, args, false)
+	if err != nil {
+		return nil, err
+	}
+	return result{res}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	res, err := c.execute(ctx, query, args, true)
+	if err != nil {
+		return nil, err
+	}
+	return &rows{result: res}, nil
+}
+
+// Ping resolves the database and runs a trivial statement on it.
+func (c *conn) Ping(ctx context.Context) error {
+	_, err := c.execute(ctx, "SELECT 1", nil, true)
+	return err
+}
+
+func (c *conn) execute(ctx context.Context, query string, args []driver.NamedValue, wantRows bool) (*hrana.Result, error) {
+	params := statementArgs(args)
+	if c.tx != nil {
+		var res *hrana.Result
+		var err error
+		if wantRows {
+			res, err = c.tx.Query(ctx, query, params...)
+		} else {
+			res, err = c.tx.Exec(ctx, query, params...)
+		}
+		return res, c.connector.checkError(c.txClient, err)
+	}
+	client, err := c.connector.hranaClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Execute(ctx, query, params...)
+	return res, c.connector.checkError(client, err)
+}
+
+// statementArgs converts driver arguments to hrana arguments, keeping names
+// as sql.NamedArg.
+func statementArgs(args []driver.NamedValue) []interface{} {
+	params := make([]interface{}, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			params[i] = sql.Named(arg.Name, arg.Value)
+		} else {
+			params[i] = arg.Value
+		}
+	}
+	return params
+}
+
+type transaction struct {
+	conn *conn
+}
+
+func (t *transaction) Commit() error {
+	return t.end((*hrana.Tx).Commit)
+}
+
+func (t *transaction) Rollback() error {
+	return t.end((*hrana.Tx).Rollback)
+}
+
+func (t *transaction) end(end func(*hrana.Tx, context.Context) error) error {
+	tx := t.conn.tx
+	if tx == nil {
+		return sql.ErrTxDone
+	}
+	t.conn.tx, t.conn.txClient = nil, nil
+	if err := end(tx, context.Background()); err != nil {
+		if errors.Is(err, hrana.ErrTxDone) {
+			return sql.ErrTxDone
+		}
+		return err
+	}
+	return nil
+}
+
+// stmt is a statement prepared with conn.Prepare.
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+var (
+	_ driver.StmtExecContext  = (*stmt)(nil)
+	_ driver.StmtQueryContext = (*stmt)(nil)
+)
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+// NumInput returns -1: the driver does not parse statements to count their
+// parameters.
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+type result struct {
+	result *hrana.Result
+}
+
+func (r result) LastInsertId() (int64, error) {
+	return r.result.LastInsertRowID, nil
+}
+
+func (r result) RowsAffected() (int64, error) {
+	return r.result.AffectedRowCount, nil
+}
+
+// rows iterates over the rows of a result, which Hrana returns in full.
+type rows struct {
+	result *hrana.Result
+	next   int
+}
+
+var _ driver.RowsColumnTypeDatabaseTypeName = (*rows)(nil)
+
+func (r *rows) Columns() []string {
+	names := make([]string, len(r.result.Columns))
+	for i, col := range r.result.Columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+func (r *rows) Close() error {
+	r.next = len(r.result.Rows)
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.Rows) {
+		return io.EOF
+	}
+	row := r.result.Rows[r.next]
+	r.next++
+	for i := range dest {
+		dest[i] = row[i]
+	}
+	return nil
+}
+
+// ColumnTypeDatabaseTypeName returns the declared type of the column, empty
+// for expressions.
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	return strings.ToUpper(r.result.Columns[index].DeclType)
+}
@@ -0,0 +1,233 @@
+package tursosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mr-destructive/turso-go"
+	"github.com/mr-destructive/turso-go/hrana"
+)
+
+const (
+	// defaultTokenExpiration is the lifetime of minted tokens unless
+	// WithTokenExpiration says otherwise.
+	defaultTokenExpiration = time.Hour
+	// maxRefreshMargin bounds how long before it expires a token is
+	// replaced. Shorter-lived tokens are replaced after 90% of their
+	// lifetime.
+	maxRefreshMargin = time.Minute
+)
+
+// Connector opens connections to one database. Its connections share the
+// hostname of the database and the token minted for it.
+type Connector struct {
+	client        *turso.Client
+	org           string
+	database      string
+	expiration    time.Duration
+	authorization turso.Authorization
+	hranaOpts     []hrana.Option
+	now           func() time.Time
+
+	mu       sync.Mutex
+	hostname string
+	conn     *hrana.Client
+	// refreshAt is when conn is replaced with one using a new token, and
+	// expiresAt when that token expires; both are zero for tokens that
+	// never expire.
+	refreshAt time.Time
+	expiresAt time.Time
+	// refreshing is closed once the lookup or mint in progress, if any,
+	// finishes.
+	refreshing chan struct{}
+}
+
+var _ driver.Connector = (*Connector)(nil)
+
+// Option configures a Connector created by NewConnector.
+type Option func(*Connector)
+
+// WithTokenExpiration sets the lifetime of minted tokens, in whole seconds,
+// or turso.NeverExpires. The default is one hour.
+func WithTokenExpiration(expiration time.Duration) Option {
+	return func(c *Connector) {
+		c.expiration = expiration
+	}
+}
+
+// WithAuthorization sets the access level of minted tokens.
+func WithAuthorization(authorization turso.Authorization) Option {
+	return func(c *Connector) {
+		c.authorization = authorization
+	}
+}
+
+// WithHranaOptions configures the hrana clients running the statements.
+func WithHranaOptions(opts ...hrana.Option) Option {
+	return func(c *Connector) {
+		c.hranaOpts = append(c.hranaOpts, opts...)
+	}
+}
+
+// NewConnector returns a Connector for the database dbName of the
+// organization orgSlug, resolved and authorized with client.
+func NewConnector(client *turso.Client, orgSlug, dbName string, opts ...Option) (*Connector, error) {
+	if client == nil {
+		return nil, fmt.Errorf("tursosql: client is required")
+	}
+	if orgSlug == "" || dbName == "" {
+		return nil, fmt.Errorf("tursosql: organization and database are required")
+	}
+	c := &Connector{
+		client:     client,
+		org:        orgSlug,
+		database:   dbName,
+		expiration: defaultTokenExpiration,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := validExpiration(c.expiration); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Connect returns a connection to the database. It does not reach the
+// network: the database is resolved by the first statement.
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &conn{connector: c}, nil
+}
+
+// Driver returns the "turso" driver.
+func (c *Connector) Driver() driver.Driver {
+	return &Driver{}
+}
+
+// hranaClient returns a client for the database with a valid token, looking
+// the database up on first use and minting a new token when the current
+// one is about to expire. Only one caller reaches the API at a time, without
+// holding mu: the others keep using the current client while it is valid,
+// or wait for the new one.
+func (c *Connector) hranaClient(ctx context.Context) (*hrana.Client, error) {
+	for {
+		c.mu.Lock()
+		// While a refresh is in progress, conn is still used until its token
+		// expires.
+		now := c.now()
+		if c.conn != nil && (c.refreshAt.IsZero() || now.Before(c.refreshAt) || (c.refreshing != nil && now.Before(c.expiresAt))) {
+			conn := c.conn
+			c.mu.Unlock()
+			return conn, nil
+		}
+		if refreshing := c.refreshing; refreshing != nil {
+			c.mu.Unlock()
+			select {
+			case <-refreshing:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		refreshing := make(chan struct{})
+		c.refreshing = refreshing
+		hostname := c.hostname
+		c.mu.Unlock()
+
+		conn, refreshAt, expiresAt, err := c.connect(ctx, &hostname)
+		c.mu.Lock()
+		c.refreshing = nil
+		c.hostname = hostname
+		if err == nil {
+			c.conn, c.refreshAt, c.expiresAt = conn, refreshAt, expiresAt
+		}
+		c.mu.Unlock()
+		close(refreshing)
+		return conn, err
+	}
+}
+
+// connect looks the database up unless *hostname is already known, mints a
+// token and returns a client using it, with the times to replace it and
+// when its token expires.
+func (c *Connector) connect(ctx context.Context, hostname *string) (conn *hrana.Client, refreshAt, expiresAt time.Time, err error) {
+	if *hostname == "" {
+		db, err := c.client.Organizations.DatabaseContext(ctx, c.org, c.database)
+		if err != nil {
+			return nil, time.Time{}, time.Time{}, fmt.Errorf("tursosql: looking up database %s/%s: %w", c.org, c.database, err)
+		}
+		*hostname = db.Hostname
+	}
+	mintedAt := c.now()
+	token, err := c.client.Organizations.MintTokenContext(ctx, c.org, c.database, turso.MintTokenOptions{
+		Expiration:    c.expiration,
+		Authorization: c.authorization,
+	})
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, fmt.Errorf("tursosql: minting a token for %s/%s: %w", c.org, c.database, err)
+	}
+	conn, err = hrana.NewClient(*hostname, token.JWT, c.hranaOpts...)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	if token.Claims != nil {
+		expiresAt = token.Claims.ExpiresAt
+	}
+	expiresAt = tokenExpiry(mintedAt, expiresAt, c.expiration)
+	return conn, refreshTime(mintedAt, expiresAt), expiresAt, nil
+}
+
+// validExpiration checks that tokens can be minted with expiration, which
+// the API takes in whole seconds.
+func validExpiration(expiration time.Duration) error {
+	if expiration == turso.NeverExpires {
+		return nil
+	}
+	if expiration < time.Second || expiration%time.Second != 0 {
+		return fmt.Errorf("tursosql: invalid token expiration %s: must be a positive number of seconds", expiration)
+	}
+	return nil
+}
+
+// tokenExpiry returns when a token minted at mintedAt expires, or zero
+// when it never does. The expiration claim, when decoded, wins over the
+// requested lifetime.
+func tokenExpiry(mintedAt, claimed time.Time, expiration time.Duration) time.Time {
+	if !claimed.IsZero() || expiration == turso.NeverExpires {
+		return claimed
+	}
+	return mintedAt.Add(expiration)
+}
+
+// refreshTime returns when a token minted at mintedAt and expiring at
+// expiresAt should be replaced.
+func refreshTime(mintedAt, expiresAt time.Time) time.Time {
+	if expiresAt.IsZero() {
+		return expiresAt
+	}
+	margin := expiresAt.Sub(mintedAt) / 10
+	if margin > maxRefreshMargin {
+		margin = maxRefreshMargin
+	}
+	return expiresAt.Add(-margin)
+}
+
+// checkError drops the cached token when the database rejected it, so that
+// the next statement mints a new one.
+func (c *Connector) checkError(client *hrana.Client, err error) error {
+	var herr *hrana.Error
+	if errors.As(err, &herr) && herr.StatusCode == http.StatusUnauthorized {
+		c.mu.Lock()
+		if c.conn == client {
+			c.conn = nil
+		}
+		c.mu.Unlock()
+	}
+	return err
+}
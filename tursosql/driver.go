@@ -0,0 +1,125 @@
+// Package tursosql is a database/sql driver for Turso databases, registered
+// as "turso". A data source name names a database of an organization:
+//
+//	db, err := sql.Open("turso", "turso://org_slug/my_db")
+//
+// The driver looks up the hostname of the database and mints a token for it
+// with the platform API token read from TURSO_AUTH_TOKEN, then runs
+// statements over the Hrana HTTP protocol. Minted tokens are cached and
+// refreshed before they expire.
+//
+// The data source name accepts these query parameters:
+//
+//	authorization  "full-access" (the default) or "read-only"
+//	expiration     lifetime of minted tokens in whole seconds, such as "1h" (the default), or "never"
+//	api_url        base URL of the platform API
+//
+// NewConnector builds a connector from an existing platform client, for use
+// with sql.OpenDB.
+package tursosql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mr-destructive/turso-go"
+)
+
+// DriverName is the name the driver is registered under.
+const DriverName = "turso"
+
+func init() {
+	sql.Register(DriverName, &Driver{})
+}
+
+// Driver is the "turso" database/sql driver.
+type Driver struct{}
+
+var (
+	_ driver.Driver        = (*Driver)(nil)
+	_ driver.DriverContext = (*Driver)(nil)
+)
+
+// Open returns a connection to the database named by dsn. The connection
+// does not share its token with other connections; sql.Open uses
+// OpenConnector instead.
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(context.Background())
+}
+
+// OpenConnector returns a Connector for the database named by dsn.
+func (d *Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	cfg, err := parseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	apiToken := os.Getenv("TURSO_AUTH_TOKEN")
+	if apiToken == "" {
+		return nil, fmt.Errorf("tursosql: TURSO_AUTH_TOKEN is required")
+	}
+	client, err := turso.NewClient(cfg.apiURL, apiToken)
+	if err != nil {
+		return nil, fmt.Errorf("tursosql: %w", err)
+	}
+	return NewConnector(client, cfg.org, cfg.database, cfg.opts...)
+}
+
+type dsnConfig struct {
+	org      string
+	database string
+	apiURL   string
+	opts     []Option
+}
+
+// parseDSN parses a turso://org/database data source name.
+func parseDSN(dsn string) (*dsnConfig, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("tursosql: invalid data source name: %w", err)
+	}
+	if u.Scheme != DriverName {
+		return nil, fmt.Errorf("tursosql: data source name must start with turso://, got %q", dsn)
+	}
+	cfg := &dsnConfig{org: u.Host, database: strings.Trim(u.Path, "/")}
+	if cfg.org == "" || cfg.database == "" || strings.Contains(cfg.database, "/") {
+		return nil, fmt.Errorf("tursosql: data source name must be turso://org/database, got %q", dsn)
+	}
+	for key, values := range u.Query() {
+		v := values[len(values)-1]
+		switch key {
+		case "authorization":
+			switch a := turso.Authorization(v); a {
+			case turso.FullAccess, turso.ReadOnly:
+				cfg.opts = append(cfg.opts, WithAuthorization(a))
+			default:
+				return nil, fmt.Errorf("tursosql: unknown authorization %q", v)
+			}
+		case "expiration":
+			expiration := turso.NeverExpires
+			if v != "never" {
+				if expiration, err = time.ParseDuration(v); err != nil || expiration <= 0 {
+					return nil, fmt.Errorf("tursosql: invalid expiration %q", v)
+				}
+			}
+			if err := validExpiration(expiration); err != nil {
+				return nil, err
+			}
+			cfg.opts = append(cfg.opts, WithTokenExpiration(expiration))
+		case "api_url":
+			cfg.apiURL = v
+		default:
+			return nil, fmt.Errorf("tursosql: unknown data source name parameter %q", key)
+		}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,353 @@
+package tursosql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mr-destructive/turso-go"
+	"github.com/mr-destructive/turso-go/turstest"
+)
+
+// clock is a manually advanced time source.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestDB returns a database/sql handle on a fake database resolved
+// through fake organizations, which mint tokens for the requested
+// expiration on the test clock.
+func newTestDB(t *testing.T, opts ...Option) (*sql.DB, *turstest.DatabaseServer, *turstest.Organizations, *clock) {
+	t.Helper()
+	server := turstest.NewDatabaseServer()
+	t.Cleanup(server.Close)
+	server.Handle("SELECT 1", turstest.Returns(&turstest.Result{Columns: []string{"1"}, Rows: [][]interface{}{{1}}}))
+	now := &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	orgs := &turstest.Organizations{
+		DatabaseFunc: func(ctx context.Context, orgSlug, dbName string) (*turso.Database, error) {
+			return &turso.Database{Name: dbName, Hostname: server.URL}, nil
+		},
+		MintTokenFunc: func(ctx context.Context, orgSlug, dbName string, opts turso.MintTokenOptions) (*turso.JWTToken, error) {
+			return &turso.JWTToken{
+				JWT:    server.Token,
+				Claims: &turso.TokenClaims{ExpiresAt: now.Now().Add(opts.Expiration)},
+			}, nil
+		},
+	}
+	connector, err := NewConnector(&turso.Client{Organizations: orgs}, "my-org", "my-db", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connector.now = now.Now
+	db := sql.OpenDB(connector)
+	t.Cleanup(func() { db.Close() })
+	return db, server, orgs, now
+}
+
+func methods(calls []turstest.Call) []string {
+	var names []string
+	for _, call := range calls {
+		names = append(names, call.Method)
+	}
+	return names
+}
+
+func countCalls(calls []turstest.Call, method string) int {
+	n := 0
+	for _, call := range calls {
+		if call.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+func TestQueryAndExec(t *testing.T) {
+	db, server, orgs, _ := newTestDB(t, WithAuthorization(turso.ReadOnly))
+	server.Handle("INSERT INTO users (name) VALUES (?)", turstest.Returns(&turstest.Result{AffectedRowCount: 1, LastInsertRowID: 7}))
+	server.Handle("SELECT id, name FROM users WHERE name = :name", turstest.Returns(&turstest.Result{
+		Columns: []string{"id", "name"},
+		Rows:    [][]interface{}{{7, "ada"}},
+	}))
+	ctx := context.Background()
+
+	res, err := db.ExecContext(ctx, "INSERT INTO users (name) VALUES (?)", "ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := res.LastInsertId(); id != 7 {
+		t.Errorf("LastInsertId = %d, want 7", id)
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		t.Errorf("RowsAffected = %d, want 1", n)
+	}
+
+	var id int64
+	var name string
+	if err := db.QueryRowContext(ctx, "SELECT id, name FROM users WHERE name = :name", sql.Named("name", "ada")).Scan(&id, &name); err != nil {
+		t.Fatal(err)
+	}
+	if id != 7 || name != "ada" {
+		t.Errorf("scanned %d, %q", id, name)
+	}
+
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO users (name) VALUES (?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	if _, err := stmt.ExecContext(ctx, "grace"); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := orgs.Calls()
+	if got := methods(calls); strings.Join(got, ",") != "Database,MintToken" {
+		t.Fatalf("calls %v, want the database looked up and one token minted", got)
+	}
+	if opts := calls[1].Args[2].(turso.MintTokenOptions); opts.Authorization != turso.ReadOnly || opts.Expiration != time.Hour {
+		t.Errorf("minted with %+v", opts)
+	}
+}
+
+func TestStatementError(t *testing.T) {
+	db, server, _, _ := newTestDB(t)
+	server.Handle("INSERT INTO users (id) VALUES (1)", func(turstest.Stmt) (*turstest.Result, error) {
+		return nil, &turstest.SQLError{Message: "UNIQUE constraint failed: users.id", Code: "SQLITE_CONSTRAINT"}
+	})
+	_, err := db.Exec("INSERT INTO users (id) VALUES (1)")
+	if err == nil || !strings.Contains(err.Error(), "SQLITE_CONSTRAINT") {
+		t.Errorf("expected a constraint error, got %v", err)
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	db, server, _, _ := newTestDB(t)
+	server.Handle("UPDATE accounts SET balance = balance + ? WHERE id = ?", turstest.Returns(&turstest.Result{AffectedRowCount: 1}))
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance + ? WHERE id = ?", -10, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err = db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, stmt := range server.Executed() {
+		got = append(got, stmt.SQL)
+	}
+	want := "BEGIN|UPDATE accounts SET balance = balance + ? WHERE id = ?|COMMIT|BEGIN|ROLLBACK"
+	if strings.Join(got, "|") != want {
+		t.Errorf("executed %q", got)
+	}
+	if server.OpenStreams() != 0 {
+		t.Errorf("%d streams left open", server.OpenStreams())
+	}
+
+	if _, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted}); err == nil {
+		t.Error("expected an unsupported isolation level error")
+	}
+}
+
+func TestTokenRefresh(t *testing.T) {
+	db, _, orgs, now := newTestDB(t, WithTokenExpiration(10*time.Minute))
+	ctx := context.Background()
+	mints := func() int { return countCalls(orgs.Calls(), "MintToken") }
+
+	if err := db.PingContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	now.Advance(8 * time.Minute)
+	if err := db.PingContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if mints() != 1 {
+		t.Fatalf("minted %d tokens, want the first one reused", mints())
+	}
+	// The token is replaced a minute before it expires.
+	now.Advance(time.Minute + time.Second)
+	if err := db.PingContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if mints() != 2 {
+		t.Fatalf("minted %d tokens, want a refreshed token", mints())
+	}
+}
+
+func TestConcurrentMint(t *testing.T) {
+	db, _, orgs, _ := newTestDB(t)
+	mint := orgs.MintTokenFunc
+	started, release := make(chan struct{}), make(chan struct{})
+	orgs.MintTokenFunc = func(ctx context.Context, orgSlug, dbName string, opts turso.MintTokenOptions) (*turso.JWTToken, error) {
+		close(started)
+		<-release
+		return mint(ctx, orgSlug, dbName, opts)
+	}
+
+	first := make(chan error, 1)
+	go func() { first <- db.Ping() }()
+	<-started
+	// A caller giving up while the token is minted is not stuck behind it.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := db.PingContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to stop the wait, got %v", err)
+	}
+	second := make(chan error, 1)
+	go func() { second <- db.Ping() }()
+	close(release)
+	for _, errc := range []chan error{first, second} {
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := countCalls(orgs.Calls(), "MintToken"); n != 1 {
+		t.Errorf("minted %d tokens, want one shared by every caller", n)
+	}
+}
+
+func TestRefreshReusesOnlyValidTokens(t *testing.T) {
+	db, _, orgs, now := newTestDB(t, WithTokenExpiration(10*time.Minute))
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	mint := orgs.MintTokenFunc
+	started, release := make(chan struct{}), make(chan struct{})
+	orgs.MintTokenFunc = func(ctx context.Context, orgSlug, dbName string, opts turso.MintTokenOptions) (*turso.JWTToken, error) {
+		close(started)
+		<-release
+		return mint(ctx, orgSlug, dbName, opts)
+	}
+	now.Advance(9*time.Minute + time.Second)
+	refreshed := make(chan error, 1)
+	go func() { refreshed <- db.Ping() }()
+	<-started
+
+	// The token due for a refresh is still valid, so it is used meanwhile.
+	if err := db.Ping(); err != nil {
+		t.Fatalf("expected the current token to be used during the refresh, got %v", err)
+	}
+	// Once it expired, callers wait for the new token.
+	now.Advance(time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := db.PingContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected to wait for the new token, got %v", err)
+	}
+	close(release)
+	if err := <-refreshed; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRejectedTokenIsDropped(t *testing.T) {
+	db, server, orgs, _ := newTestDB(t)
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	server.Token = "rotated"
+	if err := db.Ping(); err == nil {
+		t.Fatal("expected the database to reject the token")
+	}
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	if got := methods(orgs.Calls()); strings.Join(got, ",") != "Database,MintToken,MintToken" {
+		t.Errorf("calls %v, want a new token after the rejection", got)
+	}
+}
+
+func TestLookupError(t *testing.T) {
+	orgs := &turstest.Organizations{}
+	connector, err := NewConnector(&turso.Client{Organizations: orgs}, "my-org", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	if err := db.Ping(); !errors.Is(err, turstest.ErrNotScripted) {
+		t.Errorf("expected the lookup error, got %v", err)
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	cfg, err := parseDSN("turso://my-org/my-db?authorization=read-only&expiration=30m&api_url=http://localhost:8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.org != "my-org" || cfg.database != "my-db" || cfg.apiURL != "http://localhost:8080" {
+		t.Errorf("parsed %+v", cfg)
+	}
+	connector := &Connector{}
+	for _, opt := range cfg.opts {
+		opt(connector)
+	}
+	if connector.authorization != turso.ReadOnly || connector.expiration != 30*time.Minute {
+		t.Errorf("options set %q and %s", connector.authorization, connector.expiration)
+	}
+
+	for _, dsn := range []string{
+		"libsql://my-org/my-db",
+		"turso://my-org",
+		"turso://my-org/a/b",
+		"turso://my-org/my-db?expiration=soon",
+		"turso://my-org/my-db?expiration=0s",
+		"turso://my-org/my-db?expiration=1500ms",
+		"turso://my-org/my-db?authorization=admin",
+		"turso://my-org/my-db?token=secret",
+	} {
+		if _, err := parseDSN(dsn); err == nil {
+			t.Errorf("parseDSN(%q) should fail", dsn)
+		}
+	}
+}
+
+func TestNewConnectorExpiration(t *testing.T) {
+	client := &turso.Client{Organizations: &turstest.Organizations{}}
+	if _, err := NewConnector(client, "my-org", "my-db", WithTokenExpiration(90*time.Second)); err != nil {
+		t.Errorf("expected whole seconds to be accepted, got %v", err)
+	}
+	if _, err := NewConnector(client, "my-org", "my-db", WithTokenExpiration(turso.NeverExpires)); err != nil {
+		t.Errorf("expected NeverExpires to be accepted, got %v", err)
+	}
+	if _, err := NewConnector(client, "my-org", "my-db", WithTokenExpiration(time.Second/2)); err == nil {
+		t.Error("expected an error for a fractional expiration")
+	}
+}
+
+func TestOpenRequiresAPIToken(t *testing.T) {
+	t.Setenv("TURSO_AUTH_TOKEN", "")
+	_, err := sql.Open(DriverName, "turso://my-org/my-db")
+	if err == nil || !strings.Contains(err.Error(), "TURSO_AUTH_TOKEN") {
+		t.Errorf("expected a missing token error, got %v", err)
+	}
+}
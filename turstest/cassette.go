@@ -0,0 +1,248 @@
+package turstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Mode selects whether a Recorder records real traffic or replays it.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette file and fails every
+	// request that has no recording.
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests to the real transport and records them.
+	ModeRecord
+)
+
+// Redacted replaces secrets in recorded interactions.
+const Redacted = "REDACTED"
+
+// jwtPattern matches JWTs. Only their signature is redacted, so that
+// replayed tokens keep their claims but cannot be used.
+var jwtPattern = regexp.MustCompile(`(eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*\.)[A-Za-z0-9_-]*`)
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records request/response pairs to
+// a cassette file, or replays them from it. Authorization headers and JWT
+// signatures are redacted before they are written.
+type Recorder struct {
+	mode      Mode
+	path      string
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecorder returns a Recorder for the cassette at path. In ModeReplay
+// the cassette must exist; in ModeRecord requests are sent with transport,
+// or http.DefaultTransport when it is nil, and the cassette is written by
+// Save.
+func NewRecorder(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	r := &Recorder{mode: mode, path: path, transport: transport}
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("turstest: reading cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			return nil, fmt.Errorf("turstest: decoding cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.interactions))
+	}
+	return r, nil
+}
+
+// NewCassette returns a Recorder for the cassette at path that is saved
+// when the test ends. Use its Client, or pass it to turso.WithTransport.
+func NewCassette(t testing.TB, path string, mode Mode) *Recorder {
+	t.Helper()
+	r, err := NewRecorder(path, mode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := r.Save(); err != nil {
+			t.Error(err)
+		}
+	})
+	return r
+}
+
+// Client returns an http.Client using the Recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the recorded or loaded interactions.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the cassette file. It does
+// nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("turstest: writing cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("turstest: writing cassette: %w", err)
+	}
+	return nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	r.mu.Lock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.RawQuery,
+			Header: redactHeader(req.Header),
+			Body:   redact(string(body)),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       redact(string(respBody)),
+		},
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.interactions {
+		if r.used[i] || !matches(interaction.Request, req, body) {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("turstest: no recorded interaction in %s for %s %s", r.path, req.Method, req.URL.RequestURI())
+}
+
+func matches(recorded RecordedRequest, req *http.Request, body []byte) bool {
+	if recorded.Method != req.Method || recorded.Path != req.URL.Path || recorded.Query != req.URL.RawQuery {
+		return false
+	}
+	return equalBodies(recorded.Body, redact(string(body)))
+}
+
+// equalBodies compares JSON bodies by value and other bodies byte for byte.
+func equalBodies(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var left, right interface{}
+	if json.Unmarshal([]byte(a), &left) != nil || json.Unmarshal([]byte(b), &right) != nil {
+		return false
+	}
+	normalizedLeft, _ := json.Marshal(left)
+	normalizedRight, _ := json.Marshal(right)
+	return bytes.Equal(normalizedLeft, normalizedRight)
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func redactHeader(header http.Header) http.Header {
+	redacted := http.Header{}
+	for key, values := range header {
+		for _, value := range values {
+			if http.CanonicalHeaderKey(key) == "Authorization" {
+				value = Redacted
+			}
+			redacted.Add(key, redact(value))
+		}
+	}
+	return redacted
+}
+
+func redact(s string) string {
+	return jwtPattern.ReplaceAllString(s, "${1}"+Redacted)
+}
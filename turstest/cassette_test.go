@@ -0,0 +1,76 @@
+package turstest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mr-destructive/turso-go"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "databases.json")
+	server := NewServer()
+	defer server.Close()
+	server.AddDatabase(DefaultOrganization, DefaultGroup, "app")
+
+	recorder, err := NewRecorder(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := ClientFor(server, turso.WithHTTPClient(recorder.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Organizations.Databases(DefaultOrganization); err != nil {
+		t.Fatal(err)
+	}
+	jwt, err := client.Organizations.MintToken(DefaultOrganization, "app", turso.MintTokenOptions{Authorization: turso.ReadOnly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Organizations.UpdateDatabaseConfiguration(DefaultOrganization, "app", map[string]string{"size_limit": "1gb"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), server.Token) || strings.Contains(string(data), jwt.JWT) {
+		t.Error("cassette should not contain the API token or minted JWTs")
+	}
+
+	replayer, err := NewRecorder(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err = turso.NewClient("http://turso.invalid", "other-token", turso.WithHTTPClient(replayer.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	databases, err := client.Organizations.Databases(DefaultOrganization)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(databases.Databases) != 1 || databases.Databases[0].Name != "app" {
+		t.Errorf("unexpected replayed databases %+v", databases)
+	}
+	replayed, err := client.Organizations.MintToken(DefaultOrganization, "app", turso.MintTokenOptions{Authorization: turso.ReadOnly})
+	if err != nil || !strings.HasSuffix(replayed.JWT, "."+Redacted) || replayed.Claims.Authorization != turso.ReadOnly {
+		t.Errorf("unexpected replayed token %+v: %v", replayed, err)
+	}
+	if _, err := client.Organizations.UpdateDatabaseConfiguration(DefaultOrganization, "app", map[string]string{"size_limit": "2gb"}); err == nil {
+		t.Error("requests with a different body should not match")
+	}
+	if _, err := client.Organizations.UpdateDatabaseConfiguration(DefaultOrganization, "app", map[string]string{"size_limit": "1gb"}); err != nil {
+		t.Error(err)
+	}
+	_, err = client.Organizations.Databases(DefaultOrganization)
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("requests without a recording should fail, got %v", err)
+	}
+}
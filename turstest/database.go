@@ -0,0 +1,51 @@
+package turstest
+
+import (
+	"testing"
+
+	"github.com/mr-destructive/turso-go/hrana"
+	"github.com/mr-destructive/turso-go/internal/fakehrana"
+)
+
+// DatabaseServer fakes the Hrana HTTP endpoints of a database. There is no
+// SQL engine behind it: statements are answered by the handlers registered
+// with Handle, except BEGIN, COMMIT and ROLLBACK, which it tracks per
+// stream.
+type DatabaseServer = fakehrana.Server
+
+type (
+	// Stmt is a statement received by a DatabaseServer.
+	Stmt = fakehrana.Stmt
+	// Result is the result returned by a statement handler.
+	Result = fakehrana.Result
+	// SQLError fails a statement with a SQLite error code.
+	SQLError = fakehrana.Error
+	// StmtHandler answers the statements registered with Handle.
+	StmtHandler = fakehrana.Handler
+)
+
+// DefaultDatabaseToken is the token accepted by a new DatabaseServer.
+const DefaultDatabaseToken = fakehrana.DefaultToken
+
+// NewDatabaseServer starts a DatabaseServer. The caller must Close it.
+func NewDatabaseServer() *DatabaseServer {
+	return fakehrana.NewServer()
+}
+
+// Returns is a StmtHandler always answering result.
+func Returns(result *Result) StmtHandler {
+	return fakehrana.Returns(result)
+}
+
+// NewDatabaseClient starts a DatabaseServer and returns a hrana client
+// pointed at it. The server is closed when the test ends.
+func NewDatabaseClient(t testing.TB, opts ...hrana.Option) (*hrana.Client, *DatabaseServer) {
+	t.Helper()
+	server := NewDatabaseServer()
+	t.Cleanup(server.Close)
+	client, err := hrana.NewClient(server.URL, server.Token, opts...)
+	if err != nil {
+		t.Fatalf("turstest: creating database client: %v", err)
+	}
+	return client, server
+}
@@ -0,0 +1,26 @@
+package turstest
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDatabaseClient(t *testing.T) {
+	client, server := NewDatabaseClient(t)
+	server.Handle("SELECT name FROM users WHERE id = ?", func(stmt Stmt) (*Result, error) {
+		if stmt.Args[0] != int64(1) {
+			return nil, &SQLError{Message: "no such user", Code: "SQLITE_NOTFOUND"}
+		}
+		return &Result{Columns: []string{"name"}, Rows: [][]interface{}{{"ada"}}}, nil
+	})
+	result, err := client.Execute(context.Background(), "SELECT name FROM users WHERE id = ?", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows[0][0] != "ada" {
+		t.Errorf("unexpected rows %v", result.Rows)
+	}
+	if _, err := client.Execute(context.Background(), "SELECT name FROM users WHERE id = ?", 2); err == nil {
+		t.Error("expected the handler error")
+	}
+}
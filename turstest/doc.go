@@ -0,0 +1,4 @@
+// Package turstest provides utilities for testing code that depends on the
+// Turso platform API client or the hrana database client without network
+// access.
+package turstest
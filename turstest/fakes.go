@@ -0,0 +1,661 @@
+package turstest
+
+import (
+	"context"
+	"io"
+
+	"github.com/mr-destructive/turso-go"
+)
+
+// Tokens is a scriptable in-memory turso.TokensService. Each method and its
+// Context variant call the Func field named after the method, or return
+// ErrNotScripted when it is nil.
+type Tokens struct {
+	recorder
+	ListFunc     func(ctx context.Context) (*turso.TokenList, error)
+	MintFunc     func(ctx context.Context, name string) (*turso.Token, error)
+	RevokeFunc   func(ctx context.Context, tokenName string) error
+	ValidateFunc func(ctx context.Context) (*turso.TokenValidation, error)
+}
+
+var _ turso.TokensService = (*Tokens)(nil)
+
+func (f *Tokens) List() (*turso.TokenList, error) {
+	return f.ListContext(context.Background())
+}
+
+func (f *Tokens) ListContext(ctx context.Context) (*turso.TokenList, error) {
+	f.record("List")
+	if f.ListFunc == nil {
+		return nil, notScripted("Tokens.List")
+	}
+	return f.ListFunc(ctx)
+}
+
+func (f *Tokens) Mint(name string) (*turso.Token, error) {
+	return f.MintContext(context.Background(), name)
+}
+
+func (f *Tokens) MintContext(ctx context.Context, name string) (*turso.Token, error) {
+	f.record("Mint", name)
+	if f.MintFunc == nil {
+		return nil, notScripted("Tokens.Mint")
+	}
+	return f.MintFunc(ctx, name)
+}
+
+func (f *Tokens) Revoke(tokenName string) error {
+	return f.RevokeContext(context.Background(), tokenName)
+}
+
+func (f *Tokens) RevokeContext(ctx context.Context, tokenName string) error {
+	f.record("Revoke", tokenName)
+	if f.RevokeFunc == nil {
+		return notScripted("Tokens.Revoke")
+	}
+	return f.RevokeFunc(ctx, tokenName)
+}
+
+func (f *Tokens) Validate() (*turso.TokenValidation, error) {
+	return f.ValidateContext(context.Background())
+}
+
+func (f *Tokens) ValidateContext(ctx context.Context) (*turso.TokenValidation, error) {
+	f.record("Validate")
+	if f.ValidateFunc == nil {
+		return nil, notScripted("Tokens.Validate")
+	}
+	return f.ValidateFunc(ctx)
+}
+
+// Organizations is a scriptable in-memory turso.OrganizationsService. Each method and its
+// Context variant call the Func field named after the method, or return
+// ErrNotScripted when it is nil.
+type Organizations struct {
+	recorder
+	ListFunc                          func(ctx context.Context) (*turso.OrganisationList, error)
+	UpdateFunc                        func(ctx context.Context, organizationSlug string, body map[string]string) error
+	MembersFunc                       func(ctx context.Context, organizationSlug string) (*turso.MemberList, error)
+	AddMembersFunc                    func(ctx context.Context, organizationSlug string, body map[string]string) error
+	RemoveMembersFunc                 func(ctx context.Context, organizationSlug, username string) error
+	MintTokenFunc                     func(ctx context.Context, organizationSlug, dbName string, opts turso.MintTokenOptions) (*turso.JWTToken, error)
+	MintGroupTokenFunc                func(ctx context.Context, orgSlug, groupName string, opts turso.MintTokenOptions) (*turso.JWTToken, error)
+	InvalidateTokensFunc              func(ctx context.Context, organizationSlug, dbName string) error
+	DatabasesFunc                     func(ctx context.Context, organizationSlug string) (*turso.DatabaseList, error)
+	DatabaseFunc                      func(ctx context.Context, orgSlug, dbName string) (*turso.Database, error)
+	CreateDatabaseFunc                func(ctx context.Context, orgSlug string, body turso.CreateDatabaseRequest) (*turso.Database, error)
+	RetrieveDatabaseConfigurationFunc func(ctx context.Context, orgSlug, dbName string) (*turso.DatabaseConfiguration, error)
+	UpdateDatabaseConfigurationFunc   func(ctx context.Context, orgSlug, dbName string, body map[string]string) (*turso.DatabaseConfiguration, error)
+	DeleteDatabaseFunc                func(ctx context.Context, orgSlug, dbName string) error
+	UpdateDatabasesInGroupFunc        func(ctx context.Context, orgSlug, groupName string) error
+	UpdateAllInstancesFunc            func(ctx context.Context, orgSlug, dbName string) error
+	DBUsageFunc                       func(ctx context.Context, orgSlug, dbName string) (*turso.DBMonthlyUsage, error)
+	InstancesFunc                     func(ctx context.Context, orgSlug, dbName string) (*turso.InstanceList, error)
+	InstanceFunc                      func(ctx context.Context, orgSlug, dbName, instanceName string) (*turso.Instance, error)
+	CreateInstanceFunc                func(ctx context.Context, orgSlug, dbName string, body map[string]string) (*turso.Instance, error)
+	DeleteInstanceFunc                func(ctx context.Context, orgSlug, dbName, instanceName string) error
+	ListGroupsFunc                    func(ctx context.Context, orgSlug string) (*turso.GroupList, error)
+	GroupFunc                         func(ctx context.Context, orgSlug, groupName string) (*turso.OrganizationGroup, error)
+	CreateGroupFunc                   func(ctx context.Context, orgSlug string, body turso.CreateGroupRequest) (*turso.OrganizationGroup, error)
+	DeleteGroupFunc                   func(ctx context.Context, orgSlug, groupName string) error
+	AddLocationToGroupFunc            func(ctx context.Context, orgSlug, groupName, location string) (*turso.OrganizationGroup, error)
+	RemoveLocationFromGroupFunc       func(ctx context.Context, orgSlug, groupName, location string) (*turso.OrganizationGroup, error)
+	UploadDumpFileFunc                func(ctx context.Context, orgSlug string, file io.Reader) error
+	InvalidateAllDBTokensFunc         func(ctx context.Context, orgSlug, dbName string) error
+	RotateGroupTokensFunc             func(ctx context.Context, orgSlug, groupName string) error
+	ListInvitesFunc                   func(ctx context.Context, orgSlug string) (*turso.OrganizationInvites, error)
+	CreateInviteFunc                  func(ctx context.Context, orgSlug string, body map[string]string) (*turso.OrganizationInvite, error)
+	TransferOrganisationFunc          func(ctx context.Context, orgSlug, groupName, ToOrgSlug string) (*turso.OrganizationGroup, error)
+	DatabaseStatsFunc                 func(ctx context.Context, orgSlug, dbName string) (*turso.DatabaseStats, error)
+	ListPlansFunc                     func(ctx context.Context, orgSlug string) (*turso.Plan, error)
+	CurrentSubscriptionFunc           func(ctx context.Context, orgSlug string) (*turso.Subscription, error)
+	ListInvoicesFunc                  func(ctx context.Context, orgSlug string) (*turso.Invoices, error)
+	OrganisationUsageFunc             func(ctx context.Context, orgSlug string) (*turso.OrganizationUsage, error)
+}
+
+var _ turso.OrganizationsService = (*Organizations)(nil)
+
+func (f *Organizations) List() (*turso.OrganisationList, error) {
+	return f.ListContext(context.Background())
+}
+
+func (f *Organizations) ListContext(ctx context.Context) (*turso.OrganisationList, error) {
+	f.record("List")
+	if f.ListFunc == nil {
+		return nil, notScripted("Organizations.List")
+	}
+	return f.ListFunc(ctx)
+}
+
+func (f *Organizations) Update(organizationSlug string, body map[string]string) error {
+	return f.UpdateContext(context.Background(), organizationSlug, body)
+}
+
+func (f *Organizations) UpdateContext(ctx context.Context, organizationSlug string, body map[string]string) error {
+	f.record("Update", organizationSlug, body)
+	if f.UpdateFunc == nil {
+		return notScripted("Organizations.Update")
+	}
+	return f.UpdateFunc(ctx, organizationSlug, body)
+}
+
+func (f *Organizations) Members(organizationSlug string) (*turso.MemberList, error) {
+	return f.MembersContext(context.Background(), organizationSlug)
+}
+
+func (f *Organizations) MembersContext(ctx context.Context, organizationSlug string) (*turso.MemberList, error) {
+	f.record("Members", organizationSlug)
+	if f.MembersFunc == nil {
+		return nil, notScripted("Organizations.Members")
+	}
+	return f.MembersFunc(ctx, organizationSlug)
+}
+
+func (f *Organizations) AddMembers(organizationSlug string, body map[string]string) error {
+	return f.AddMembersContext(context.Background(), organizationSlug, body)
+}
+
+func (f *Organizations) AddMembersContext(ctx context.Context, organizationSlug string, body map[string]string) error {
+	f.record("AddMembers", organizationSlug, body)
+	if f.AddMembersFunc == nil {
+		return notScripted("Organizations.AddMembers")
+	}
+	return f.AddMembersFunc(ctx, organizationSlug, body)
+}
+
+func (f *Organizations) RemoveMembers(organizationSlug, username string) error {
+	return f.RemoveMembersContext(context.Background(), organizationSlug, username)
+}
+
+func (f *Organizations) RemoveMembersContext(ctx context.Context, organizationSlug, username string) error {
+	f.record("RemoveMembers", organizationSlug, username)
+	if f.RemoveMembersFunc == nil {
+		return notScripted("Organizations.RemoveMembers")
+	}
+	return f.RemoveMembersFunc(ctx, organizationSlug, username)
+}
+
+func (f *Organizations) MintToken(organizationSlug, dbName string, opts turso.MintTokenOptions) (*turso.JWTToken, error) {
+	return f.MintTokenContext(context.Background(), organizationSlug, dbName, opts)
+}
+
+func (f *Organizations) MintTokenContext(ctx context.Context, organizationSlug, dbName string, opts turso.MintTokenOptions) (*turso.JWTToken, error) {
+	f.record("MintToken", organizationSlug, dbName, opts)
+	if f.MintTokenFunc == nil {
+		return nil, notScripted("Organizations.MintToken")
+	}
+	return f.MintTokenFunc(ctx, organizationSlug, dbName, opts)
+}
+
+func (f *Organizations) MintGroupToken(orgSlug, groupName string, opts turso.MintTokenOptions) (*turso.JWTToken, error) {
+	return f.MintGroupTokenContext(context.Background(), orgSlug, groupName, opts)
+}
+
+func (f *Organizations) MintGroupTokenContext(ctx context.Context, orgSlug, groupName string, opts turso.MintTokenOptions) (*turso.JWTToken, error) {
+	f.record("MintGroupToken", orgSlug, groupName, opts)
+	if f.MintGroupTokenFunc == nil {
+		return nil, notScripted("Organizations.MintGroupToken")
+	}
+	return f.MintGroupTokenFunc(ctx, orgSlug, groupName, opts)
+}
+
+func (f *Organizations) InvalidateTokens(organizationSlug, dbName string) error {
+	return f.InvalidateTokensContext(context.Background(), organizationSlug, dbName)
+}
+
+func (f *Organizations) InvalidateTokensContext(ctx context.Context, organizationSlug, dbName string) error {
+	f.record("InvalidateTokens", organizationSlug, dbName)
+	if f.InvalidateTokensFunc == nil {
+		return notScripted("Organizations.InvalidateTokens")
+	}
+	return f.InvalidateTokensFunc(ctx, organizationSlug, dbName)
+}
+
+func (f *Organizations) Databases(organizationSlug string) (*turso.DatabaseList, error) {
+	return f.DatabasesContext(context.Background(), organizationSlug)
+}
+
+func (f *Organizations) DatabasesContext(ctx context.Context, organizationSlug string) (*turso.DatabaseList, error) {
+	f.record("Databases", organizationSlug)
+	if f.DatabasesFunc == nil {
+		return nil, notScripted("Organizations.Databases")
+	}
+	return f.DatabasesFunc(ctx, organizationSlug)
+}
+
+func (f *Organizations) Database(orgSlug, dbName string) (*turso.Database, error) {
+	return f.DatabaseContext(context.Background(), orgSlug, dbName)
+}
+
+func (f *Organizations) DatabaseContext(ctx context.Context, orgSlug, dbName string) (*turso.Database, error) {
+	f.record("Database", orgSlug, dbName)
+	if f.DatabaseFunc == nil {
+		return nil, notScripted("Organizations.Database")
+	}
+	return f.DatabaseFunc(ctx, orgSlug, dbName)
+}
+
+func (f *Organizations) CreateDatabase(orgSlug string, body turso.CreateDatabaseRequest) (*turso.Database, error) {
+	return f.CreateDatabaseContext(context.Background(), orgSlug, body)
+}
+
+func (f *Organizations) CreateDatabaseContext(ctx context.Context, orgSlug string, body turso.CreateDatabaseRequest) (*turso.Database, error) {
+	f.record("CreateDatabase", orgSlug, body)
+	if f.CreateDatabaseFunc == nil {
+		return nil, notScripted("Organizations.CreateDatabase")
+	}
+	return f.CreateDatabaseFunc(ctx, orgSlug, body)
+}
+
+func (f *Organizations) RetrieveDatabaseConfiguration(orgSlug, dbName string) (*turso.DatabaseConfiguration, error) {
+	return f.RetrieveDatabaseConfigurationContext(context.Background(), orgSlug, dbName)
+}
+
+func (f *Organizations) RetrieveDatabaseConfigurationContext(ctx context.Context, orgSlug, dbName string) (*turso.DatabaseConfiguration, error) {
+	f.record("RetrieveDatabaseConfiguration", orgSlug, dbName)
+	if f.RetrieveDatabaseConfigurationFunc == nil {
+		return nil, notScripted("Organizations.RetrieveDatabaseConfiguration")
+	}
+	return f.RetrieveDatabaseConfigurationFunc(ctx, orgSlug, dbName)
+}
+
+func (f *Organizations) UpdateDatabaseConfiguration(orgSlug, dbName string, body map[string]string) (*turso.DatabaseConfiguration, error) {
+	return f.UpdateDatabaseConfigurationContext(context.Background(), orgSlug, dbName, body)
+}
+
+func (f *Organizations) UpdateDatabaseConfigurationContext(ctx context.Context, orgSlug, dbName string, body map[string]string) (*turso.DatabaseConfiguration, error) {
+	f.record("UpdateDatabaseConfiguration", orgSlug, dbName, body)
+	if f.UpdateDatabaseConfigurationFunc == nil {
+		return nil, notScripted("Organizations.UpdateDatabaseConfiguration")
+	}
+	return f.UpdateDatabaseConfigurationFunc(ctx, orgSlug, dbName, body)
+}
+
+func (f *Organizations) DeleteDatabase(orgSlug, dbName string) error {
+	return f.DeleteDatabaseContext(context.Background(), orgSlug, dbName)
+}
+
+func (f *Organizations) DeleteDatabaseContext(ctx context.Context, orgSlug, dbName string) error {
+	f.record("DeleteDatabase", orgSlug, dbName)
+	if f.DeleteDatabaseFunc == nil {
+		return notScripted("Organizations.DeleteDatabase")
+	}
+	return f.DeleteDatabaseFunc(ctx, orgSlug, dbName)
+}
+
+func (f *Organizations) UpdateDatabasesInGroup(orgSlug, groupName string) error {
+	return f.UpdateDatabasesInGroupContext(context.Background(), orgSlug, groupName)
+}
+
+func (f *Organizations) UpdateDatabasesInGroupContext(ctx context.Context, orgSlug, groupName string) error {
+	f.record("UpdateDatabasesInGroup", orgSlug, groupName)
+	if f.UpdateDatabasesInGroupFunc == nil {
+		return notScripted("Organizations.UpdateDatabasesInGroup")
+	}
+	return f.UpdateDatabasesInGroupFunc(ctx, orgSlug, groupName)
+}
+
+func (f *Organizations) UpdateAllInstances(orgSlug, dbName string) error {
+	return f.UpdateAllInstancesContext(context.Background(), orgSlug, dbName)
+}
+
+func (f *Organizations) UpdateAllInstancesContext(ctx context.Context, orgSlug, dbName string) error {
+	f.record("UpdateAllInstances", orgSlug, dbName)
+	if f.UpdateAllInstancesFunc == nil {
+		return notScripted("Organizations.UpdateAllInstances")
+	}
+	return f.UpdateAllInstancesFunc(ctx, orgSlug, dbName)
+}
+
+func (f *Organizations) DBUsage(orgSlug, dbName string) (*turso.DBMonthlyUsage, error) {
+	return f.DBUsageContext(context.Background(), orgSlug, dbName)
+}
+
+func (f *Organizations) DBUsageContext(ctx context.Context, orgSlug, dbName string) (*turso.DBMonthlyUsage, error) {
+	f.record("DBUsage", orgSlug, dbName)
+	if f.DBUsageFunc == nil {
+		return nil, notScripted("Organizations.DBUsage")
+	}
+	return f.DBUsageFunc(ctx, orgSlug, dbName)
+}
+
+func (f *Organizations) Instances(orgSlug, dbName string) (*turso.InstanceList, error) {
+	return f.InstancesContext(context.Background(), orgSlug, dbName)
+}
+
+func (f *Organizations) InstancesContext(ctx context.Context, orgSlug, dbName string) (*turso.InstanceList, error) {
+	f.record("Instances", orgSlug, dbName)
+	if f.InstancesFunc == nil {
+		return nil, notScripted("Organizations.Instances")
+	}
+	return f.InstancesFunc(ctx, orgSlug, dbName)
+}
+
+func (f *Organizations) Instance(orgSlug, dbName, instanceName string) (*turso.Instance, error) {
+	return f.InstanceContext(context.Background(), orgSlug, dbName, instanceName)
+}
+
+func (f *Organizations) InstanceContext(ctx context.Context, orgSlug, dbName, instanceName string) (*turso.Instance, error) {
+	f.record("Instance", orgSlug, dbName, instanceName)
+	if f.InstanceFunc == nil {
+		return nil, notScripted("Organizations.Instance")
+	}
+	return f.InstanceFunc(ctx, orgSlug, dbName, instanceName)
+}
+
+func (f *Organizations) CreateInstance(orgSlug, dbName string, body map[string]string) (*turso.Instance, error) {
+	return f.CreateInstanceContext(context.Background(), orgSlug, dbName, body)
+}
+
+func (f *Organizations) CreateInstanceContext(ctx context.Context, orgSlug, dbName string, body map[string]string) (*turso.Instance, error) {
+	f.record("CreateInstance", orgSlug, dbName, body)
+	if f.CreateInstanceFunc == nil {
+		return nil, notScripted("Organizations.CreateInstance")
+	}
+	return f.CreateInstanceFunc(ctx, orgSlug, dbName, body)
+}
+
+func (f *Organizations) DeleteInstance(orgSlug, dbName, instanceName string) error {
+	return f.DeleteInstanceContext(context.Background(), orgSlug, dbName, instanceName)
+}
+
+func (f *Organizations) DeleteInstanceContext(ctx context.Context, orgSlug, dbName, instanceName string) error {
+	f.record("DeleteInstance", orgSlug, dbName, instanceName)
+	if f.DeleteInstanceFunc == nil {
+		return notScripted("Organizations.DeleteInstance")
+	}
+	return f.DeleteInstanceFunc(ctx, orgSlug, dbName, instanceName)
+}
+
+func (f *Organizations) ListGroups(orgSlug string) (*turso.GroupList, error) {
+	return f.ListGroupsContext(context.Background(), orgSlug)
+}
+
+func (f *Organizations) ListGroupsContext(ctx context.Context, orgSlug string) (*turso.GroupList, error) {
+	f.record("ListGroups", orgSlug)
+	if f.ListGroupsFunc == nil {
+		return nil, notScripted("Organizations.ListGroups")
+	}
+	return f.ListGroupsFunc(ctx, orgSlug)
+}
+
+func (f *Organizations) Group(orgSlug, groupName string) (*turso.OrganizationGroup, error) {
+	return f.GroupContext(context.Background(), orgSlug, groupName)
+}
+
+func (f *Organizations) GroupContext(ctx context.Context, orgSlug, groupName string) (*turso.OrganizationGroup, error) {
+	f.record("Group", orgSlug, groupName)
+	if f.GroupFunc == nil {
+		return nil, notScripted("Organizations.Group")
+	}
+	return f.GroupFunc(ctx, orgSlug, groupName)
+}
+
+func (f *Organizations) CreateGroup(orgSlug string, body turso.CreateGroupRequest) (*turso.OrganizationGroup, error) {
+	return f.CreateGroupContext(context.Background(), orgSlug, body)
+}
+
+func (f *Organizations) CreateGroupContext(ctx context.Context, orgSlug string, body turso.CreateGroupRequest) (*turso.OrganizationGroup, error) {
+	f.record("CreateGroup", orgSlug, body)
+	if f.CreateGroupFunc == nil {
+		return nil, notScripted("Organizations.CreateGroup")
+	}
+	return f.CreateGroupFunc(ctx, orgSlug, body)
+}
+
+func (f *Organizations) DeleteGroup(orgSlug, groupName string) error {
+	return f.DeleteGroupContext(context.Background(), orgSlug, groupName)
+}
+
+func (f *Organizations) DeleteGroupContext(ctx context.Context, orgSlug, groupName string) error {
+	f.record("DeleteGroup", orgSlug, groupName)
+	if f.DeleteGroupFunc == nil {
+		return notScripted("Organizations.DeleteGroup")
+	}
+	return f.DeleteGroupFunc(ctx, orgSlug, groupName)
+}
+
+func (f *Organizations) AddLocationToGroup(orgSlug, groupName, location string) (*turso.OrganizationGroup, error) {
+	return f.AddLocationToGroupContext(context.Background(), orgSlug, groupName, location)
+}
+
+func (f *Organizations) AddLocationToGroupContext(ctx context.Context, orgSlug, groupName, location string) (*turso.OrganizationGroup, error) {
+	f.record("AddLocationToGroup", orgSlug, groupName, location)
+	if f.AddLocationToGroupFunc == nil {
+		return nil, notScripted("Organizations.AddLocationToGroup")
+	}
+	return f.AddLocationToGroupFunc(ctx, orgSlug, groupName, location)
+}
+
+func (f *Organizations) RemoveLocationFromGroup(orgSlug, groupName, location string) (*turso.OrganizationGroup, error) {
+	return f.RemoveLocationFromGroupContext(context.Background(), orgSlug, groupName, location)
+}
+
+func (f *Organizations) RemoveLocationFromGroupContext(ctx context.Context, orgSlug, groupName, location string) (*turso.OrganizationGroup, error) {
+	f.record("RemoveLocationFromGroup", orgSlug, groupName, location)
+	if f.RemoveLocationFromGroupFunc == nil {
+		return nil, notScripted("Organizations.RemoveLocationFromGroup")
+	}
+	return f.RemoveLocationFromGroupFunc(ctx, orgSlug, groupName, location)
+}
+
+func (f *Organizations) UploadDumpFile(orgSlug string, file io.Reader) error {
+	return f.UploadDumpFileContext(context.Background(), orgSlug, file)
+}
+
+func (f *Organizations) UploadDumpFileContext(ctx context.Context, orgSlug string, file io.Reader) error {
+	f.record("UploadDumpFile", orgSlug, file)
+	if f.UploadDumpFileFunc == nil {
+		return notScripted("Organizations.UploadDumpFile")
+	}
+	return f.UploadDumpFileFunc(ctx, orgSlug, file)
+}
+
+func (f *Organizations) InvalidateAllDBTokens(orgSlug, dbName string) error {
+	return f.InvalidateAllDBTokensContext(context.Background(), orgSlug, dbName)
+}
+
+func (f *Organizations) InvalidateAllDBTokensContext(ctx context.Context, orgSlug, dbName string) error {
+	f.record("InvalidateAllDBTokens", orgSlug, dbName)
+	if f.InvalidateAllDBTokensFunc == nil {
+		return notScripted("Organizations.InvalidateAllDBTokens")
+	}
+	return f.InvalidateAllDBTokensFunc(ctx, orgSlug, dbName)
+}
+
+func (f *Organizations) RotateGroupTokens(orgSlug, groupName string) error {
+	return f.RotateGroupTokensContext(context.Background(), orgSlug, groupName)
+}
+
+func (f *Organizations) RotateGroupTokensContext(ctx context.Context, orgSlug, groupName string) error {
+	f.record("RotateGroupTokens", orgSlug, groupName)
+	if f.RotateGroupTokensFunc == nil {
+		return notScripted("Organizations.RotateGroupTokens")
+	}
+	return f.RotateGroupTokensFunc(ctx, orgSlug, groupName)
+}
+
+// InvalidateAllGroupTokens calls RotateGroupTokens, like the client.
+//
+// Deprecated: Use RotateGroupTokens.
+func (f *Organizations) InvalidateAllGroupTokens(orgSlug, groupName, token string) error {
+	return f.RotateGroupTokensContext(context.Background(), orgSlug, groupName)
+}
+
+// InvalidateAllGroupTokensContext calls RotateGroupTokensContext, like the
+// client.
+//
+// Deprecated: Use RotateGroupTokensContext.
+func (f *Organizations) InvalidateAllGroupTokensContext(ctx context.Context, orgSlug, groupName, token string) error {
+	return f.RotateGroupTokensContext(ctx, orgSlug, groupName)
+}
+
+func (f *Organizations) ListInvites(orgSlug string) (*turso.OrganizationInvites, error) {
+	return f.ListInvitesContext(context.Background(), orgSlug)
+}
+
+func (f *Organizations) ListInvitesContext(ctx context.Context, orgSlug string) (*turso.OrganizationInvites, error) {
+	f.record("ListInvites", orgSlug)
+	if f.ListInvitesFunc == nil {
+		return nil, notScripted("Organizations.ListInvites")
+	}
+	return f.ListInvitesFunc(ctx, orgSlug)
+}
+
+func (f *Organizations) CreateInvite(orgSlug string, body map[string]string) (*turso.OrganizationInvite, error) {
+	return f.CreateInviteContext(context.Background(), orgSlug, body)
+}
+
+func (f *Organizations) CreateInviteContext(ctx context.Context, orgSlug string, body map[string]string) (*turso.OrganizationInvite, error) {
+	f.record("CreateInvite", orgSlug, body)
+	if f.CreateInviteFunc == nil {
+		return nil, notScripted("Organizations.CreateInvite")
+	}
+	return f.CreateInviteFunc(ctx, orgSlug, body)
+}
+
+func (f *Organizations) TransferOrganisation(orgSlug, groupName, ToOrgSlug string) (*turso.OrganizationGroup, error) {
+	return f.TransferOrganisationContext(context.Background(), orgSlug, groupName, ToOrgSlug)
+}
+
+func (f *Organizations) TransferOrganisationContext(ctx context.Context, orgSlug, groupName, ToOrgSlug string) (*turso.OrganizationGroup, error) {
+	f.record("TransferOrganisation", orgSlug, groupName, ToOrgSlug)
+	if f.TransferOrganisationFunc == nil {
+		return nil, notScripted("Organizations.TransferOrganisation")
+	}
+	return f.TransferOrganisationFunc(ctx, orgSlug, groupName, ToOrgSlug)
+}
+
+func (f *Organizations) DatabaseStats(orgSlug, dbName string) (*turso.DatabaseStats, error) {
+	return f.DatabaseStatsContext(context.Background(), orgSlug, dbName)
+}
+
+func (f *Organizations) DatabaseStatsContext(ctx context.Context, orgSlug, dbName string) (*turso.DatabaseStats, error) {
+	f.record("DatabaseStats", orgSlug, dbName)
+	if f.DatabaseStatsFunc == nil {
+		return nil, notScripted("Organizations.DatabaseStats")
+	}
+	return f.DatabaseStatsFunc(ctx, orgSlug, dbName)
+}
+
+func (f *Organizations) ListPlans(orgSlug string) (*turso.Plan, error) {
+	return f.ListPlansContext(context.Background(), orgSlug)
+}
+
+func (f *Organizations) ListPlansContext(ctx context.Context, orgSlug string) (*turso.Plan, error) {
+	f.record("ListPlans", orgSlug)
+	if f.ListPlansFunc == nil {
+		return nil, notScripted("Organizations.ListPlans")
+	}
+	return f.ListPlansFunc(ctx, orgSlug)
+}
+
+func (f *Organizations) CurrentSubscription(orgSlug string) (*turso.Subscription, error) {
+	return f.CurrentSubscriptionContext(context.Background(), orgSlug)
+}
+
+func (f *Organizations) CurrentSubscriptionContext(ctx context.Context, orgSlug string) (*turso.Subscription, error) {
+	f.record("CurrentSubscription", orgSlug)
+	if f.CurrentSubscriptionFunc == nil {
+		return nil, notScripted("Organizations.CurrentSubscription")
+	}
+	return f.CurrentSubscriptionFunc(ctx, orgSlug)
+}
+
+func (f *Organizations) ListInvoices(orgSlug string) (*turso.Invoices, error) {
+	return f.ListInvoicesContext(context.Background(), orgSlug)
+}
+
+func (f *Organizations) ListInvoicesContext(ctx context.Context, orgSlug string) (*turso.Invoices, error) {
+	f.record("ListInvoices", orgSlug)
+	if f.ListInvoicesFunc == nil {
+		return nil, notScripted("Organizations.ListInvoices")
+	}
+	return f.ListInvoicesFunc(ctx, orgSlug)
+}
+
+func (f *Organizations) OrganisationUsage(orgSlug string) (*turso.OrganizationUsage, error) {
+	return f.OrganisationUsageContext(context.Background(), orgSlug)
+}
+
+func (f *Organizations) OrganisationUsageContext(ctx context.Context, orgSlug string) (*turso.OrganizationUsage, error) {
+	f.record("OrganisationUsage", orgSlug)
+	if f.OrganisationUsageFunc == nil {
+		return nil, notScripted("Organizations.OrganisationUsage")
+	}
+	return f.OrganisationUsageFunc(ctx, orgSlug)
+}
+
+// Locations is a scriptable in-memory turso.LocationsService. Each method and its
+// Context variant call the Func field named after the method, or return
+// ErrNotScripted when it is nil.
+type Locations struct {
+	recorder
+	ListFunc    func(ctx context.Context) (*turso.LocationList, error)
+	ClosestFunc func(ctx context.Context) (*turso.Region, error)
+}
+
+var _ turso.LocationsService = (*Locations)(nil)
+
+func (f *Locations) List() (*turso.LocationList, error) {
+	return f.ListContext(context.Background())
+}
+
+func (f *Locations) ListContext(ctx context.Context) (*turso.LocationList, error) {
+	f.record("List")
+	if f.ListFunc == nil {
+		return nil, notScripted("Locations.List")
+	}
+	return f.ListFunc(ctx)
+}
+
+func (f *Locations) Closest() (*turso.Region, error) {
+	return f.ClosestContext(context.Background())
+}
+
+func (f *Locations) ClosestContext(ctx context.Context) (*turso.Region, error) {
+	f.record("Closest")
+	if f.ClosestFunc == nil {
+		return nil, notScripted("Locations.Closest")
+	}
+	return f.ClosestFunc(ctx)
+}
+
+// AuditLogs is a scriptable in-memory turso.AuditLogsService. Each method and its
+// Context variant call the Func field named after the method, or return
+// ErrNotScripted when it is nil. List falls back to ListPageFunc when
+// ListFunc is nil.
+type AuditLogs struct {
+	recorder
+	ListFunc     func(ctx context.Context, orgSlug string) (*turso.AuditLogPage, error)
+	ListPageFunc func(ctx context.Context, orgSlug string, opts turso.AuditLogPageOptions) (*turso.AuditLogPage, error)
+}
+
+var _ turso.AuditLogsService = (*AuditLogs)(nil)
+
+func (f *AuditLogs) List(orgSlug string) (*turso.AuditLogPage, error) {
+	return f.ListContext(context.Background(), orgSlug)
+}
+
+func (f *AuditLogs) ListContext(ctx context.Context, orgSlug string) (*turso.AuditLogPage, error) {
+	f.record("List", orgSlug)
+	if f.ListFunc != nil {
+		return f.ListFunc(ctx, orgSlug)
+	}
+	if f.ListPageFunc != nil {
+		return f.ListPageFunc(ctx, orgSlug, turso.AuditLogPageOptions{})
+	}
+	return nil, notScripted("AuditLogs.List")
+}
+
+func (f *AuditLogs) ListPage(orgSlug string, opts turso.AuditLogPageOptions) (*turso.AuditLogPage, error) {
+	return f.ListPageContext(context.Background(), orgSlug, opts)
+}
+
+func (f *AuditLogs) ListPageContext(ctx context.Context, orgSlug string, opts turso.AuditLogPageOptions) (*turso.AuditLogPage, error) {
+	f.record("ListPage", orgSlug, opts)
+	if f.ListPageFunc == nil {
+		return nil, notScripted("AuditLogs.ListPage")
+	}
+	return f.ListPageFunc(ctx, orgSlug, opts)
+}
@@ -0,0 +1,52 @@
+package turstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mr-destructive/turso-go"
+)
+
+func TestFakeOrganizations(t *testing.T) {
+	organizations := &Organizations{
+		DatabasesFunc: func(ctx context.Context, orgSlug string) (*turso.DatabaseList, error) {
+			return &turso.DatabaseList{Databases: []turso.Database{{Name: "db"}}}, nil
+		},
+	}
+	client := &turso.Client{Organizations: organizations}
+	databases, err := client.Organizations.Databases("org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(databases.Databases) != 1 || databases.Databases[0].Name != "db" {
+		t.Errorf("unexpected databases %+v", databases)
+	}
+	calls := organizations.Calls()
+	if len(calls) != 1 || calls[0].Method != "Databases" || calls[0].Args[0] != "org" {
+		t.Errorf("unexpected calls %+v", calls)
+	}
+	if _, err := client.Organizations.Database("org", "db"); !errors.Is(err, ErrNotScripted) {
+		t.Errorf("expected ErrNotScripted, got %v", err)
+	}
+}
+
+func TestAuditLogsIterateAuditLogs(t *testing.T) {
+	fake := &AuditLogs{
+		ListPageFunc: func(ctx context.Context, orgSlug string, opts turso.AuditLogPageOptions) (*turso.AuditLogPage, error) {
+			return &turso.AuditLogPage{
+				AuditLogs:  []turso.AuditLog{{Code: fmt.Sprintf("page-%d", opts.Page)}},
+				Pagination: turso.Pagination{Page: opts.Page, TotalPages: 2},
+			}, nil
+		},
+	}
+	it := turso.IterateAuditLogs(context.Background(), fake, "org", turso.AuditLogFilter{})
+	var codes []string
+	for it.Next() {
+		codes = append(codes, it.AuditLog().Code)
+	}
+	if it.Err() != nil || len(codes) != 2 || codes[0] != "page-1" || codes[1] != "page-2" {
+		t.Errorf("got %v, %v", codes, it.Err())
+	}
+}
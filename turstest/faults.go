@@ -0,0 +1,177 @@
+package turstest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrConnectionDropped is returned by a FaultTransport when it drops a
+// request.
+var ErrConnectionDropped = errors.New("turstest: connection dropped")
+
+// Fault describes the failures injected into matching requests. Rates are
+// probabilities between 0 and 1.
+type Fault struct {
+	// Method matches the request method; empty matches every method.
+	Method string
+	// Path is a path.Match pattern such as "/v1/organizations/*/databases";
+	// empty matches every path.
+	Path string
+	// Latency is added before the request is sent.
+	Latency time.Duration
+	// DropRate is the probability of failing with ErrConnectionDropped
+	// without sending the request.
+	DropRate float64
+	// StatusRates maps a status code, such as 429 or 503, to the
+	// probability of answering with it without sending the request.
+	StatusRates map[int]float64
+	// RetryAfter is set as the Retry-After header of injected statuses.
+	RetryAfter time.Duration
+	// TruncateRate is the probability of cutting the response body in half.
+	TruncateRate float64
+}
+
+func (f Fault) matches(req *http.Request) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, req.Method) {
+		return false
+	}
+	if f.Path == "" {
+		return true
+	}
+	matched, err := path.Match(f.Path, req.URL.Path)
+	return err == nil && matched
+}
+
+// FaultTransport is an http.RoundTripper injecting latency, dropped
+// connections, error statuses and truncated bodies into the requests
+// matching its faults. The first matching fault applies. Failures are drawn
+// from a seeded source so that a run can be reproduced.
+type FaultTransport struct {
+	transport http.RoundTripper
+	faults    []Fault
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewFaultTransport wraps transport, or http.DefaultTransport when it is
+// nil.
+func NewFaultTransport(transport http.RoundTripper, seed int64, faults ...Fault) *FaultTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &FaultTransport{
+		transport: transport,
+		faults:    faults,
+		rng:       rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (t *FaultTransport) roll() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rng.Float64()
+}
+
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault, ok := t.match(req)
+	if !ok {
+		return t.transport.RoundTrip(req)
+	}
+	if fault.Latency > 0 {
+		timer := time.NewTimer(fault.Latency)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+	if fault.DropRate > 0 && t.roll() < fault.DropRate {
+		closeBody(req)
+		return nil, ErrConnectionDropped
+	}
+	if status, ok := t.pickStatus(fault.StatusRates); ok {
+		closeBody(req)
+		return injectedResponse(req, status, fault.RetryAfter), nil
+	}
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || fault.TruncateRate <= 0 || t.roll() >= fault.TruncateRate {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	body = body[:len(body)/2]
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+func (t *FaultTransport) match(req *http.Request) (Fault, bool) {
+	for _, fault := range t.faults {
+		if fault.matches(req) {
+			return fault, true
+		}
+	}
+	return Fault{}, false
+}
+
+// pickStatus draws at most one status, walking the codes in order so that
+// the same seed always gives the same result.
+func (t *FaultTransport) pickStatus(rates map[int]float64) (int, bool) {
+	if len(rates) == 0 {
+		return 0, false
+	}
+	statuses := make([]int, 0, len(rates))
+	for status := range rates {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	n := t.roll()
+	for _, status := range statuses {
+		if n < rates[status] {
+			return status, true
+		}
+		n -= rates[status]
+	}
+	return 0, false
+}
+
+func injectedResponse(req *http.Request, status int, retryAfter time.Duration) *http.Response {
+	body := fmt.Sprintf(`{"error": "turstest: injected %d %s"}`, status, http.StatusText(status))
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	if retryAfter > 0 {
+		header.Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
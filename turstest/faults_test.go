@@ -0,0 +1,81 @@
+package turstest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mr-destructive/turso-go"
+)
+
+func TestFaultTransportRoutes(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	transport := NewFaultTransport(nil, 1, Fault{
+		Method:      http.MethodPost,
+		Path:        "/v1/organizations/*/databases",
+		StatusRates: map[int]float64{http.StatusServiceUnavailable: 1},
+	})
+	client, err := ClientFor(server, turso.WithTransport(transport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Organizations.CreateDatabase(DefaultOrganization, turso.CreateDatabaseRequest{Name: "app", Group: DefaultGroup})
+	var apiErr *turso.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected an injected 503, got %v", err)
+	}
+	if _, err := client.Organizations.Databases(DefaultOrganization); err != nil {
+		t.Errorf("other routes should not fail: %v", err)
+	}
+}
+
+func TestFaultTransportDropAndTruncate(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	transport := NewFaultTransport(nil, 1,
+		Fault{Path: "/v1/locations", DropRate: 1},
+		Fault{Path: "/v1/organizations/*/databases", TruncateRate: 1},
+	)
+	client, err := ClientFor(server, turso.WithTransport(transport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Locations.List(); !errors.Is(err, ErrConnectionDropped) {
+		t.Errorf("expected a dropped connection, got %v", err)
+	}
+	if _, err := client.Organizations.Databases(DefaultOrganization); err == nil {
+		t.Error("a truncated body should fail to decode")
+	}
+}
+
+func TestFaultTransportSeed(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	run := func() []bool {
+		transport := NewFaultTransport(nil, 42, Fault{StatusRates: map[int]float64{429: 0.3, 500: 0.3}})
+		client, err := ClientFor(server, turso.WithTransport(transport))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var failures []bool
+		for i := 0; i < 20; i++ {
+			_, err := client.Locations.List()
+			failures = append(failures, err != nil)
+		}
+		return failures
+	}
+	first, second := run(), run()
+	failed := 0
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("runs with the same seed should fail the same requests")
+		}
+		if first[i] {
+			failed++
+		}
+	}
+	if failed == 0 || failed == len(first) {
+		t.Errorf("expected some requests to fail, got %d of %d", failed, len(first))
+	}
+}
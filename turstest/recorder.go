@@ -0,0 +1,39 @@
+package turstest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotScripted is returned by a fake method whose Func field is not set.
+var ErrNotScripted = errors.New("method not scripted")
+
+func notScripted(method string) error {
+	return fmt.Errorf("turstest: %s: %w", method, ErrNotScripted)
+}
+
+// Call is a method call recorded by a fake. Args does not include the
+// context.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the calls made to the fake, in order.
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
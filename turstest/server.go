@@ -0,0 +1,47 @@
+package turstest
+
+import (
+	"testing"
+
+	"github.com/mr-destructive/turso-go"
+	"github.com/mr-destructive/turso-go/internal/fakeapi"
+)
+
+// Server is a stateful, in-process fake of the Turso platform API covering
+// organizations, databases, instances, groups, tokens, invites, members,
+// locations, usage and audit logs. Creates and deletes are reflected in
+// later reads, requests must carry Server.Token and failures are returned
+// with the API's error bodies.
+type Server = fakeapi.Server
+
+const (
+	DefaultToken        = fakeapi.DefaultToken
+	DefaultOrganization = fakeapi.DefaultOrganization
+	DefaultGroup        = fakeapi.DefaultGroup
+	DefaultLocation     = fakeapi.DefaultLocation
+)
+
+// NewServer starts a Server with DefaultOrganization, which has a
+// DefaultGroup in DefaultLocation. The caller must Close it.
+func NewServer() *Server {
+	return fakeapi.NewServer()
+}
+
+// NewClient starts a Server and returns a client pointed at it. The server
+// is closed when the test ends.
+func NewClient(t testing.TB, opts ...turso.Option) (*turso.Client, *Server) {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+	client, err := ClientFor(server, opts...)
+	if err != nil {
+		t.Fatalf("turstest: creating client: %v", err)
+	}
+	return client, server
+}
+
+// ClientFor returns a client pointed at server.
+func ClientFor(server *Server, opts ...turso.Option) (*turso.Client, error) {
+	opts = append([]turso.Option{turso.WithRegionURL(server.RegionURL())}, opts...)
+	return turso.NewClient(server.URL, server.Token, opts...)
+}
@@ -0,0 +1,124 @@
+package turstest
+
+import (
+	"testing"
+
+	"github.com/mr-destructive/turso-go"
+)
+
+func TestServerDatabases(t *testing.T) {
+	client, _ := NewClient(t)
+	db, err := client.Organizations.CreateDatabase(DefaultOrganization, turso.CreateDatabaseRequest{Name: "app", Group: DefaultGroup})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.DbId == "" || db.Hostname == "" {
+		t.Errorf("created database should have an id and hostname: %+v", db)
+	}
+	if _, err := client.Organizations.CreateDatabase(DefaultOrganization, turso.CreateDatabaseRequest{Name: "app", Group: DefaultGroup}); !turso.IsConflict(err) {
+		t.Errorf("expected a conflict, got %v", err)
+	}
+	databases, err := client.Organizations.Databases(DefaultOrganization)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(databases.Databases) != 1 || databases.Databases[0].Name != "app" {
+		t.Errorf("unexpected databases %+v", databases)
+	}
+	instances, err := client.Organizations.Instances(DefaultOrganization, "app")
+	if err != nil || len(instances.Instances) != 1 || instances.Instances[0].Region != DefaultLocation {
+		t.Errorf("unexpected instances %+v: %v", instances, err)
+	}
+	if err := client.Organizations.DeleteDatabase(DefaultOrganization, "app"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Organizations.Database(DefaultOrganization, "app"); !turso.IsNotFound(err) {
+		t.Errorf("expected not found after delete, got %v", err)
+	}
+	logs, err := client.AuditLogs.List(DefaultOrganization)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs.AuditLogs) != 2 || logs.AuditLogs[0].Code != "db-delete" || logs.AuditLogs[1].Code != "db-create" {
+		t.Errorf("unexpected audit logs %+v", logs.AuditLogs)
+	}
+}
+
+func TestServerGroups(t *testing.T) {
+	client, server := NewClient(t)
+	server.AddOrganization("other")
+	group, err := client.Organizations.CreateGroup(DefaultOrganization, turso.CreateGroupRequest{Name: "eu", Location: "fra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.Primary != "fra" {
+		t.Errorf("unexpected group %+v", group)
+	}
+	group, err = client.Organizations.AddLocationToGroup(DefaultOrganization, "eu", "lhr")
+	if err != nil || len(group.Locations) != 2 {
+		t.Errorf("unexpected group %+v: %v", group, err)
+	}
+	if _, err := client.Organizations.TransferOrganisation(DefaultOrganization, "eu", "other"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Organizations.Group("other", "eu"); err != nil {
+		t.Errorf("group should be transferred: %v", err)
+	}
+}
+
+func TestServerMembersAndInvites(t *testing.T) {
+	client, _ := NewClient(t)
+	if err := client.Organizations.AddMembers(DefaultOrganization, map[string]string{"username": "alice", "role": "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	members, err := client.Organizations.Members(DefaultOrganization)
+	if err != nil || len(members.Members) != 2 {
+		t.Errorf("unexpected members %+v: %v", members, err)
+	}
+	if err := client.Organizations.RemoveMembers(DefaultOrganization, "bob"); !turso.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	invite, err := client.Organizations.CreateInvite(DefaultOrganization, map[string]string{"email": "bob@example.com"})
+	if err != nil || invite.Email != "bob@example.com" {
+		t.Errorf("unexpected invite %+v: %v", invite, err)
+	}
+	invites, err := client.Organizations.ListInvites(DefaultOrganization)
+	if err != nil || len(invites.Invites) != 1 {
+		t.Errorf("unexpected invites %+v: %v", invites, err)
+	}
+}
+
+func TestServerTokensAndLocations(t *testing.T) {
+	client, _ := NewClient(t)
+	token, err := client.Tokens.Mint("ci")
+	if err != nil || token.Token == "" {
+		t.Fatalf("unexpected token %+v: %v", token, err)
+	}
+	tokens, err := client.Tokens.List()
+	if err != nil || len(tokens.Tokens) != 1 || tokens.Tokens[0].Token != "" {
+		t.Errorf("unexpected tokens %+v: %v", tokens, err)
+	}
+	if err := client.Tokens.Revoke("ci"); err != nil {
+		t.Error(err)
+	}
+	locations, err := client.Locations.List()
+	if err != nil || locations.Locations[DefaultLocation] == "" {
+		t.Errorf("unexpected locations %+v: %v", locations, err)
+	}
+	region, err := client.Locations.Closest()
+	if err != nil || region.Server != DefaultLocation {
+		t.Errorf("unexpected region %+v: %v", region, err)
+	}
+}
+
+func TestServerRequiresAuth(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client, err := turso.NewClient(server.URL, "wrong-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Organizations.List(); !turso.IsUnauthorized(err) {
+		t.Errorf("expected unauthorized, got %v", err)
+	}
+}